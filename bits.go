@@ -0,0 +1,147 @@
+package imgio
+
+import (
+	"image"
+	"io"
+)
+
+// pointsIterator iterates over points of a sequence
+type pointsIterator interface {
+	Valid() bool
+	Current() image.Point
+	Next()
+}
+
+// indexIterator iterates over points of generator gen starting from index. It doesn't move the
+// cursor of the generator
+type indexIterator struct {
+	gen   PointsSequenceGenerator
+	index uint64
+}
+
+func (it *indexIterator) Valid() bool {
+	return it.index < it.gen.Len()
+}
+
+func (it *indexIterator) Current() image.Point {
+	return it.gen.Point(it.index)
+}
+
+func (it *indexIterator) Next() {
+	it.index++
+}
+
+func bitsMask(n int) uint64 {
+	return 1<<uint(n) - 1
+}
+
+// readBits reads bytes to p from points of iterator it starting from bit bitCursor of the
+// current point. Bits of a point are read from the highest to the lowest one. It returns
+// number of read bytes and bit cursor of the current point after reading
+func readBits(acc PointAccessor, it pointsIterator, bitCursor int, p []byte) (n int, _ int, err error) {
+	var (
+		buff     uint64
+		buffBits int
+	)
+
+	for n < len(p) && it.Valid() {
+		point := it.Current()
+		bits := acc.PointBits(point)
+		value := acc.ReadPointBits(point)
+
+		for bitCursor < bits && n < len(p) {
+			k := 8 - buffBits
+			if bits-bitCursor < k {
+				k = bits - bitCursor
+			}
+
+			buff = buff<<uint(k) | value>>uint(bits-bitCursor-k)&bitsMask(k)
+			buffBits += k
+			bitCursor += k
+
+			if buffBits == 8 {
+				p[n] = byte(buff)
+				n++
+				buff, buffBits = 0, 0
+			}
+		}
+
+		if bitCursor >= bits {
+			it.Next()
+			bitCursor = 0
+		}
+	}
+
+	if !it.Valid() {
+		return n, bitCursor, io.EOF
+	}
+
+	return n, bitCursor, nil
+}
+
+// writeBits writes bytes from p to points of iterator it starting from bit bitCursor of the
+// current point. Bits of a point are written from the highest to the lowest one. It returns
+// number of written bytes and bit cursor of the current point after writing
+func writeBits(acc PointAccessor, it pointsIterator, bitCursor int, p []byte) (n int, _ int, err error) {
+	var written int
+
+	for n < len(p) && it.Valid() {
+		point := it.Current()
+		bits := acc.PointBits(point)
+
+		// Stored bits have to be kept only if the point is not overwritten entirely
+		var value uint64
+		if bitCursor > 0 || (len(p)-n)*8-written < bits {
+			value = acc.ReadPointBits(point)
+		}
+
+		for bitCursor < bits && n < len(p) {
+			k := 8 - written
+			if bits-bitCursor < k {
+				k = bits - bitCursor
+			}
+
+			shift := uint(bits - bitCursor - k)
+			chunk := uint64(p[n]) >> uint(8-written-k) & bitsMask(k)
+			value = value&^(bitsMask(k)<<shift) | chunk<<shift
+			written += k
+			bitCursor += k
+
+			if written == 8 {
+				n++
+				written = 0
+			}
+		}
+
+		acc.WritePointBits(point, value)
+
+		if bitCursor >= bits {
+			it.Next()
+			bitCursor = 0
+		}
+	}
+
+	if n < len(p) {
+		return n, bitCursor, ErrOverflow
+	}
+
+	return n, bitCursor, nil
+}
+
+// bitPointAt returns index of the point in the sequence of generator gen which contains the
+// first bit of byte on position pos and bit cursor inside that point
+func bitPointAt(acc PointAccessor, gen PointsSequenceGenerator, pos int64) (uint64, int) {
+	var index uint64
+
+	bitPos := pos * 8
+
+	for length := gen.Len(); index < length; index++ {
+		bits := int64(acc.PointBits(gen.Point(index)))
+		if bitPos < bits {
+			break
+		}
+		bitPos -= bits
+	}
+
+	return index, int(bitPos)
+}
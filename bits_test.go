@@ -0,0 +1,91 @@
+package imgio
+
+import (
+	"image"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_bitPointAt(t *testing.T) {
+	prw, err := NewChannelBitsReadWriter(1, ChannelRGB)
+	require.Nil(t, err)
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 3)),
+		NewSimplePointsSequenceGenerator(image.Rect(0, 0, 3, 3)),
+		prw,
+	)
+
+	tests := []struct {
+		pos int64
+
+		expectedIndex     uint64
+		expectedBitCursor int
+	}{
+		{0, 0, 0},
+		{1, 2, 2},
+		{2, 5, 1},
+		{3, 8, 0},
+	}
+
+	for i, test := range tests {
+		index, bitCursor := bitPointAt(img.Accessor(), img.gen, test.pos)
+		require.Equal(t, test.expectedIndex, index, "Test index %d", i)
+		require.Equal(t, test.expectedBitCursor, bitCursor, "Test index %d", i)
+	}
+}
+
+func Test_newPointsLayout(t *testing.T) {
+	rect := image.Rect(0, 0, 3, 3)
+	prw, err := NewChannelBitsReadWriter(1, ChannelRGB)
+	require.Nil(t, err)
+
+	tests := []struct {
+		acc PointAccessor
+		gen PointsSequenceGenerator
+
+		expectedPointBits int
+		expectedTotalBits int64
+	}{
+		{
+			NewImageAccessor(image.NewRGBA(rect), prw),
+			NewSimplePointsSequenceGenerator(rect),
+			3,
+			27,
+		},
+		{
+			NewYCbCrAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio420), PointReadWriterYCbCrSimple{}),
+			NewSimplePointsSequenceGenerator(rect),
+			0,
+			64,
+		},
+	}
+
+	for i, test := range tests {
+		layout := newPointsLayout(test.acc, test.gen)
+		require.Equal(t, test.expectedPointBits, layout.pointBits, "Test index %d", i)
+		require.Equal(t, test.expectedTotalBits, layout.totalBits, "Test index %d", i)
+	}
+}
+
+func Test_pointsLayout_pointAt(t *testing.T) {
+	rect := image.Rect(0, 0, 7, 5)
+	prw, err := NewChannelBitsReadWriter(3, ChannelRGB)
+	require.Nil(t, err)
+	gen := NewRandPointsSequenceGenerator(rect, make([]byte, 16))
+
+	accs := []PointAccessor{
+		NewImageAccessor(image.NewRGBA(rect), prw),
+		NewYCbCrAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio420), PointReadWriterYCbCrSimple{}),
+	}
+
+	for i, acc := range accs {
+		layout := newPointsLayout(acc, gen)
+		for pos := int64(0); pos <= layout.totalBits/8; pos++ {
+			expectedIndex, expectedBitCursor := bitPointAt(acc, gen, pos)
+			index, bitCursor := layout.pointAt(acc, gen, pos)
+			require.Equal(t, expectedIndex, index, "Test index %d", i)
+			require.Equal(t, expectedBitCursor, bitCursor, "Test index %d", i)
+		}
+	}
+}
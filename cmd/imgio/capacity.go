@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ivan1993spb/imgio"
+	"github.com/urfave/cli"
+)
+
+// channelBudget is number of payload bits kept by a channel of the image
+type channelBudget struct {
+	Channel      string `json:"channel"`
+	BitsPerPoint int    `json:"bits_per_point"`
+	Bits         int64  `json:"bits"`
+}
+
+// carrierReport describes frame detected in the image
+type carrierReport struct {
+	Codec       string   `json:"codec"`
+	CodecParams uint16   `json:"codec_params"`
+	Flags       []string `json:"flags"`
+	Length      uint64   `json:"length"`
+	Checksum    string   `json:"checksum"`
+}
+
+// capacityReport describes capacity of the image for codec and points generator
+type capacityReport struct {
+	Path      string `json:"path"`
+	Format    string `json:"format"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Codec     string `json:"codec"`
+	Generator string `json:"generator"`
+
+	Points       int64           `json:"points"`
+	BitsPerPoint int             `json:"bits_per_point"`
+	Channels     []channelBudget `json:"channels"`
+
+	// Capacity is size of the storage, PayloadCapacity is size of payload which a frame keeps
+	Capacity        int64 `json:"capacity"`
+	PayloadCapacity int64 `json:"payload_capacity"`
+
+	Carrier *carrierReport `json:"carrier,omitempty"`
+}
+
+// codecChannels returns bits which codec with params keeps in channels of a point
+func codecChannels(codec imgio.CodecID, params uint16) []channelBudget {
+	channels := func(names string, bits ...int) []channelBudget {
+		budget := make([]channelBudget, len(names))
+		for i := range names {
+			budget[i] = channelBudget{Channel: names[i : i+1], BitsPerPoint: bits[i]}
+		}
+		return budget
+	}
+
+	switch codec {
+	case imgio.CodecSimplePoint32, imgio.CodecRGBASimple, imgio.CodecNRGBAPoint32:
+		return channels("RGBA", 8, 8, 8, 8)
+	case imgio.CodecSimplePoint64, imgio.CodecNRGBAPoint64:
+		return channels("RGBA", 16, 16, 16, 16)
+	case imgio.CodecGentlePoint16:
+		return channels("RGBA", 4, 4, 4, 4)
+	case imgio.CodecSmartPoint8:
+		return channels("RGB", 3, 3, 2)
+	case imgio.CodecChannelBits:
+		var budget []channelBudget
+		bits, mask := int(params>>8), imgio.ChannelMask(params)
+		for i, channel := range []imgio.ChannelMask{imgio.ChannelR, imgio.ChannelG, imgio.ChannelB, imgio.ChannelA} {
+			if mask&channel != 0 {
+				budget = append(budget, channelBudget{Channel: "RGBA"[i : i+1], BitsPerPoint: bits})
+			}
+		}
+		return budget
+	case imgio.CodecGrayLSB:
+		return channels("Y", 1)
+	case imgio.CodecGrayBits:
+		return channels("Y", int(params))
+	case imgio.CodecYCbCrSimple:
+		return []channelBudget{{Channel: "Cb", BitsPerPoint: 8}, {Channel: "Cr", BitsPerPoint: 8}}
+	case imgio.CodecYCbCrLuma:
+		return []channelBudget{{Channel: "Y", BitsPerPoint: imgio.PointReadWriterYCbCrLumaBits}}
+	case imgio.CodecJSteg:
+		return []channelBudget{{Channel: "AC", BitsPerPoint: 1}}
+	}
+	return nil
+}
+
+// chromaSamples returns number of chroma samples of img, several points of subsampled image
+// share a chroma sample. Subsampling is the same in every row and every column, so the number is
+// the product of numbers of samples in the first row and in the first column
+func chromaSamples(img *image.YCbCr) int64 {
+	rect := img.Bounds()
+	if rect.Empty() {
+		return 0
+	}
+
+	var columns, rows int64
+	for x, last := rect.Min.X, -1; x < rect.Max.X; x++ {
+		if offset := img.COffset(x, rect.Min.Y); offset != last {
+			columns++
+			last = offset
+		}
+	}
+	for y, last := rect.Min.Y, -1; y < rect.Max.Y; y++ {
+		if offset := img.COffset(rect.Min.X, y); offset != last {
+			rows++
+			last = offset
+		}
+	}
+
+	return columns * rows
+}
+
+// frameFlagNames returns names of frame flags
+func frameFlagNames(flags uint8) []string {
+	names := []string{}
+	for _, flag := range []struct {
+		flag uint8
+		name string
+	}{
+		{imgio.FrameFlagEncrypted, "encrypted"},
+		{imgio.FrameFlagCompressed, "compressed"},
+		{imgio.FrameFlagPart, "part"},
+	} {
+		if flags&flag.flag != 0 {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
+// detectCarrier returns report of frame written into image data with points sequence generator
+// newGen with key or nil if there is no frame. Checksum of the payload is verified
+func detectCarrier(data []byte, newGen func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator,
+	key []byte) *carrierReport {
+	fr, err := openPayloadGen(data, newGen, key)
+	if err != nil {
+		return nil
+	}
+
+	header := fr.Header()
+	report := &carrierReport{
+		Codec:       header.Codec.String(),
+		CodecParams: header.CodecParams,
+		Flags:       frameFlagNames(header.Flags),
+		Length:      header.Length,
+		Checksum:    "ok",
+	}
+
+	if _, err := io.Copy(ioutil.Discard, fr); err == imgio.ErrChecksum {
+		report.Checksum = "mismatch"
+	} else if err != nil {
+		report.Checksum = err.Error()
+	}
+
+	return report
+}
+
+// analyzeCapacity reports capacity of image data for codec and generator of flags of capacity
+// command. If codec flag is empty, codec of detected frame or simple32 codec is used
+func analyzeCapacity(c *cli.Context, path string, data []byte) (*capacityReport, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	newGen, ok := generators[c.String("generator")]
+	if !ok {
+		return nil, fmt.Errorf("unknown generator %q", c.String("generator"))
+	}
+	key := []byte(c.String("key"))
+
+	report := &capacityReport{
+		Path:      path,
+		Format:    format,
+		Width:     config.Width,
+		Height:    config.Height,
+		Generator: c.String("generator"),
+		Carrier:   detectCarrier(data, newGen, key),
+	}
+
+	var (
+		codec  imgio.CodecID
+		params uint16
+		prw    interface{}
+	)
+	if name := c.String("codec"); name != "" || report.Carrier == nil {
+		if name == "" {
+			name = imgio.CodecSimplePoint32.String()
+		}
+		if codec, params, prw, err = parseCodec(c, name); err != nil {
+			return nil, err
+		}
+	} else {
+		codec, _ = imgio.ParseCodecID(report.Carrier.Codec)
+		params = report.Carrier.CodecParams
+		if codec != imgio.CodecJSteg {
+			if prw, err = imgio.CodecReadWriter(codec, params); err != nil {
+				return nil, err
+			}
+		}
+	}
+	report.Codec = codec.String()
+
+	var (
+		carrier image.Image
+		storage imgio.Storage
+	)
+	if codec == imgio.CodecJSteg {
+		coeffs, err := imgio.DecodeJPEGCoefficients(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		storage = imgio.NewJPEGReadWriter(coeffs, newGen(coeffs.Bounds(), key))
+		report.Points = int64(coeffs.Bounds().Dx())
+	} else {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if carrier, storage, err = newCarrierGen(prw, img, img.Bounds(), newGen, key); err != nil {
+			return nil, err
+		}
+		report.Points = int64(config.Width) * int64(config.Height)
+	}
+
+	if report.Capacity, err = storage.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	report.PayloadCapacity = report.Capacity - int64(imgio.FrameHeaderSize)
+	if report.PayloadCapacity < 0 {
+		report.PayloadCapacity = 0
+	}
+
+	report.Channels = codecChannels(codec, params)
+	for i := range report.Channels {
+		points := report.Points
+		if ycc, ok := carrier.(*image.YCbCr); ok && strings.HasPrefix(report.Channels[i].Channel, "C") {
+			points = chromaSamples(ycc)
+		}
+		report.BitsPerPoint += report.Channels[i].BitsPerPoint
+		report.Channels[i].Bits = int64(report.Channels[i].BitsPerPoint) * points
+	}
+
+	return report, nil
+}
+
+// writeCapacityText writes reports in human readable form
+func writeCapacityText(w io.Writer, reports []*capacityReport) error {
+	buff := bytes.NewBuffer(nil)
+	var total int64
+
+	for _, report := range reports {
+		fmt.Fprintf(buff, "%s: format %s, size %dx%d\n", report.Path, report.Format, report.Width, report.Height)
+		fmt.Fprintf(buff, "  codec %s, generator %s\n", report.Codec, report.Generator)
+		fmt.Fprintf(buff, "  %d points, %d bits per point\n", report.Points, report.BitsPerPoint)
+		for _, channel := range report.Channels {
+			fmt.Fprintf(buff, "    %-2s %2d bits per point, %d bits\n", channel.Channel, channel.BitsPerPoint, channel.Bits)
+		}
+		fmt.Fprintf(buff, "  capacity %d bytes, payload up to %d bytes\n", report.Capacity, report.PayloadCapacity)
+
+		if carrier := report.Carrier; carrier != nil {
+			flags := strings.Join(carrier.Flags, ", ")
+			if flags == "" {
+				flags = "none"
+			}
+			fmt.Fprintf(buff, "  carrier: codec %s, params 0x%04x, flags %s, payload %d bytes, checksum %s\n",
+				carrier.Codec, carrier.CodecParams, flags, carrier.Length, carrier.Checksum)
+		} else {
+			fmt.Fprintln(buff, "  carrier: no payload found")
+		}
+
+		total += report.Capacity
+	}
+
+	if len(reports) > 1 {
+		fmt.Fprintf(buff, "total capacity %d bytes\n", total)
+	}
+
+	_, err := buff.WriteTo(w)
+	return err
+}
+
+// writeCapacityJSON writes reports as JSON object
+func writeCapacityJSON(w io.Writer, reports []*capacityReport) error {
+	var total int64
+	for _, report := range reports {
+		total += report.Capacity
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Images   []*capacityReport `json:"images"`
+		Capacity int64             `json:"capacity"`
+	}{reports, total})
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/ivan1993spb/imgio"
+	"github.com/urfave/cli"
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_chromaSamples(t *testing.T) {
+	tests := []struct {
+		rect  image.Rectangle
+		ratio image.YCbCrSubsampleRatio
+
+		expectedSamples int64
+	}{
+		{image.Rect(0, 0, 5, 3), image.YCbCrSubsampleRatio444, 15},
+		{image.Rect(0, 0, 5, 3), image.YCbCrSubsampleRatio422, 9},
+		{image.Rect(0, 0, 5, 3), image.YCbCrSubsampleRatio420, 6},
+		{image.Rect(0, 0, 5, 3), image.YCbCrSubsampleRatio440, 10},
+		{image.Rect(0, 0, 9, 3), image.YCbCrSubsampleRatio411, 9},
+		{image.Rect(0, 0, 9, 3), image.YCbCrSubsampleRatio410, 6},
+		{image.Rect(1, 1, 4, 4), image.YCbCrSubsampleRatio420, 4},
+		{image.Rect(0, 0, 0, 0), image.YCbCrSubsampleRatio420, 0},
+	}
+
+	for i, test := range tests {
+		img := image.NewYCbCr(test.rect, test.ratio)
+		require.Equal(t, test.expectedSamples, chromaSamples(img), "Test index %d", i)
+	}
+}
+
+// newCapacityContext returns context of capacity command with flags set to values
+func newCapacityContext(t *testing.T, values map[string]string) *cli.Context {
+	set := flag.NewFlagSet("capacity", flag.ContinueOnError)
+	set.String("codec", "", "")
+	set.Int("bits", 1, "")
+	set.String("channels", "rgb", "")
+	set.String("generator", "simple", "")
+	set.String("key", "", "")
+	for name, value := range values {
+		require.Nil(t, set.Set(name, value))
+	}
+	return cli.NewContext(nil, set, nil)
+}
+
+// newCapacityCarrier returns PNG image of size rect with payload written with nrgba32 codec and
+// points sequence generator with key
+func newCapacityCarrier(t *testing.T, rect image.Rectangle, generator, key string, payload []byte) []byte {
+	img, storage, err := newCarrierGen(imgio.NRGBAPoint32ReadWriter{}, nil, rect, generators[generator], []byte(key))
+	require.Nil(t, err)
+	require.Nil(t, writeFrame(storage, imgio.CodecNRGBAPoint32, 0, 0, payload))
+
+	buff := bytes.NewBuffer(nil)
+	require.Nil(t, png.Encode(buff, img))
+	return buff.Bytes()
+}
+
+func Test_analyzeCapacity(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 8)
+	payload := []byte("capacity of the carrier")
+
+	tests := []struct {
+		carrierGenerator string
+		carrierKey       string
+		flags            map[string]string
+
+		expectedCodec   string
+		expectedCarrier *carrierReport
+	}{
+		{
+			"simple", "",
+			map[string]string{},
+			"nrgba32",
+			&carrierReport{Codec: "nrgba32", Flags: []string{}, Length: uint64(len(payload)), Checksum: "ok"},
+		},
+		{
+			"rand", "secret",
+			map[string]string{"generator": "rand", "key": "secret"},
+			"nrgba32",
+			&carrierReport{Codec: "nrgba32", Flags: []string{}, Length: uint64(len(payload)), Checksum: "ok"},
+		},
+		{
+			"rand", "secret",
+			map[string]string{},
+			"simple32",
+			nil,
+		},
+		{
+			"simple", "",
+			map[string]string{"codec": "gray-bits", "bits": "3"},
+			"gray-bits",
+			&carrierReport{Codec: "nrgba32", Flags: []string{}, Length: uint64(len(payload)), Checksum: "ok"},
+		},
+	}
+
+	for i, test := range tests {
+		data := newCapacityCarrier(t, rect, test.carrierGenerator, test.carrierKey, payload)
+		report, err := analyzeCapacity(newCapacityContext(t, test.flags), "carrier.png", data)
+		require.Nil(t, err, "Test index %d", i)
+
+		require.Equal(t, "png", report.Format, "Test index %d", i)
+		require.Equal(t, rect.Dx(), report.Width, "Test index %d", i)
+		require.Equal(t, rect.Dy(), report.Height, "Test index %d", i)
+		require.Equal(t, test.expectedCodec, report.Codec, "Test index %d", i)
+		require.EqualValues(t, rect.Dx()*rect.Dy(), report.Points, "Test index %d", i)
+		require.Equal(t, report.Capacity-int64(imgio.FrameHeaderSize), report.PayloadCapacity, "Test index %d", i)
+		require.Equal(t, test.expectedCarrier, report.Carrier, "Test index %d", i)
+	}
+}
+
+func Test_analyzeCapacity_Budget(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 8)
+	data := newCapacityCarrier(t, rect, "simple", "", []byte("payload"))
+
+	report, err := analyzeCapacity(newCapacityContext(t, map[string]string{"codec": "gray-bits", "bits": "3"}), "-", data)
+	require.Nil(t, err)
+	require.Equal(t, 3, report.BitsPerPoint)
+	require.Equal(t, []channelBudget{{Channel: "Y", BitsPerPoint: 3, Bits: 3 * 128}}, report.Channels)
+	require.EqualValues(t, 3*128/8, report.Capacity)
+}
+
+func Test_writeCapacity(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 8)
+	payload := []byte("capacity of the carrier")
+	report, err := analyzeCapacity(newCapacityContext(t, map[string]string{}), "carrier.png",
+		newCapacityCarrier(t, rect, "simple", "", payload))
+	require.Nil(t, err)
+
+	buff := bytes.NewBuffer(nil)
+	require.Nil(t, writeCapacityText(buff, []*capacityReport{report}))
+	require.Equal(t, `carrier.png: format png, size 16x8
+  codec nrgba32, generator simple
+  128 points, 32 bits per point
+    R   8 bits per point, 1024 bits
+    G   8 bits per point, 1024 bits
+    B   8 bits per point, 1024 bits
+    A   8 bits per point, 1024 bits
+  capacity 512 bytes, payload up to 491 bytes
+  carrier: codec nrgba32, params 0x0000, flags none, payload 23 bytes, checksum ok
+`, buff.String())
+
+	buff.Reset()
+	require.Nil(t, writeCapacityJSON(buff, []*capacityReport{report, report}))
+	var actual struct {
+		Images   []*capacityReport `json:"images"`
+		Capacity int64             `json:"capacity"`
+	}
+	require.Nil(t, json.Unmarshal(buff.Bytes(), &actual))
+	require.Equal(t, []*capacityReport{report, report}, actual.Images)
+	require.EqualValues(t, 1024, actual.Capacity)
+}
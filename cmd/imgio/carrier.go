@@ -0,0 +1,287 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ivan1993spb/imgio"
+)
+
+// generators are constructors of points sequence generators by name. Encode command always uses
+// simple generator, because decode command doesn't know keys
+var generators = map[string]func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator{
+	"simple": func(rect image.Rectangle, _ []byte) imgio.PointsSequenceGenerator {
+		return imgio.NewSimplePointsSequenceGenerator(rect)
+	},
+	"rand": func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator {
+		return imgio.NewRandPointsSequenceGenerator(rect, key)
+	},
+}
+
+// newCarrier returns image suitable for point read-writer prw and storage over it with simple
+// points sequence generator
+func newCarrier(prw interface{}, cover image.Image, rect image.Rectangle) (image.Image, imgio.Storage, error) {
+	return newCarrierGen(prw, cover, rect, generators["simple"], nil)
+}
+
+// newCarrierGen returns image suitable for point read-writer prw and storage over it with points
+// sequence generator newGen with key. The image is a copy of cover converted to the type required
+// by prw. If cover is nil, the image of size rect is blank and opaque
+func newCarrierGen(prw interface{}, cover image.Image, rect image.Rectangle,
+	newGen func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator, key []byte) (image.Image, imgio.Storage, error) {
+	if cover != nil {
+		rect = cover.Bounds()
+	}
+
+	gen := newGen(rect, key)
+
+	switch prw := prw.(type) {
+	case imgio.PointReadWriterYCbCr:
+		img := newYCbCr(cover, rect)
+		return img, imgio.NewPointStorage(imgio.NewYCbCrAccessor(img, prw), gen), nil
+
+	case imgio.PointReadWriterRGBA:
+		img := image.NewRGBA(rect)
+		drawCover(img, cover, img.Pix, 4, 1)
+		return img, imgio.NewPointStorage(imgio.NewRGBAAccessor(img, prw), gen), nil
+
+	case imgio.PointReadWriter:
+		switch codec, _ := imgio.CodecOf(prw); codec {
+		case imgio.CodecNRGBAPoint32:
+			img := image.NewNRGBA(rect)
+			drawCover(img, cover, img.Pix, 4, 1)
+			return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+		case imgio.CodecNRGBAPoint64:
+			img := image.NewNRGBA64(rect)
+			drawCover(img, cover, img.Pix, 8, 2)
+			return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+		case imgio.CodecGrayLSB, imgio.CodecGrayBits:
+			img := newGray(prw, cover, rect)
+			return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+		}
+
+		if isAlphaChannelBits(prw) {
+			if isWideChannelBits(prw) {
+				img := image.NewNRGBA64(rect)
+				drawCover(img, cover, img.Pix, 8, 2)
+				return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+			}
+			img := image.NewNRGBA(rect)
+			drawCover(img, cover, img.Pix, 4, 1)
+			return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+		}
+
+		if prw.Bits(rect.Min) > imgio.SimplePoint32Capacity*8 || isWideChannelBits(prw) {
+			img := image.NewRGBA64(rect)
+			drawCover(img, cover, img.Pix, 8, 2)
+			return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+		}
+		img := image.NewRGBA(rect)
+		drawCover(img, cover, img.Pix, 4, 1)
+		return img, imgio.NewPointStorage(imgio.NewImageAccessor(img, prw), gen), nil
+	}
+
+	return nil, nil, imgio.ErrCodecUnknown
+}
+
+// carrierRect returns the smallest rectangle with ratio of width to height close to aspect which
+// keeps size bytes written with point read-writer prw
+func carrierRect(prw interface{}, size int64, aspect float64) (image.Rectangle, error) {
+	bitser, ok := prw.(interface {
+		Bits(p image.Point) int
+	})
+	if !ok {
+		return image.ZR, imgio.ErrCodecUnknown
+	}
+
+	bits := int64(bitser.Bits(image.ZP))
+	points := float64((size*8 + bits - 1) / bits)
+
+	height := int(math.Ceil(math.Sqrt(points / aspect)))
+	if height < 1 {
+		height = 1
+	}
+	width := int(math.Ceil(points / float64(height)))
+	if width < 1 {
+		width = 1
+	}
+
+	return image.Rect(0, 0, width, height), nil
+}
+
+// parseAspect parses aspect ratio like "16:9" or "1.5"
+func parseAspect(s string) (float64, error) {
+	var (
+		aspect float64
+		err    error
+	)
+
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		var width, height float64
+		if width, err = strconv.ParseFloat(s[:i], 64); err == nil {
+			height, err = strconv.ParseFloat(s[i+1:], 64)
+			aspect = width / height
+		}
+	} else {
+		aspect, err = strconv.ParseFloat(s, 64)
+	}
+
+	if err != nil || aspect <= 0 || math.IsInf(aspect, 0) || math.IsNaN(aspect) {
+		return 0, fmt.Errorf("invalid aspect ratio %q", s)
+	}
+
+	return aspect, nil
+}
+
+// backgrounds are generators of opaque images which fill carrier without cover. Pixels which
+// don't keep payload look like a photo noise or a smooth gradient instead of a flat color
+var backgrounds = map[string]func(rect image.Rectangle) image.Image{
+	"noise": func(rect image.Rectangle) image.Image {
+		return noiseImage(rect)
+	},
+	"gradient": func(rect image.Rectangle) image.Image {
+		return gradientImage(rect)
+	},
+	"blank": func(rect image.Rectangle) image.Image {
+		return nil
+	},
+}
+
+// noiseImage returns opaque image of random colors. Colors are read from crypto/rand, so noise
+// differs between runs
+func noiseImage(rect image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(rect)
+	rand.Read(img.Pix)
+	opaque(img.Pix, 4, 1)
+	return img
+}
+
+// gradientImage returns opaque image of random diagonal gradient
+func gradientImage(rect image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(rect)
+
+	var colors [6]byte
+	rand.Read(colors[:])
+
+	var from, to [3]float64
+	for i := range from {
+		from[i] = float64(colors[i])
+		to[i] = float64(colors[3+i])
+	}
+
+	size := float64(rect.Dx() + rect.Dy())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			t := float64(x-rect.Min.X+y-rect.Min.Y) / size
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(from[0] + (to[0]-from[0])*t),
+				G: uint8(from[1] + (to[1]-from[1])*t),
+				B: uint8(from[2] + (to[2]-from[2])*t),
+				A: 0xff,
+			})
+		}
+	}
+
+	return img
+}
+
+// drawCover copies cover into img or makes img opaque if there is no cover. Pixels of img are
+// stored in pix with stride bytes per pixel and size bytes per channel
+func drawCover(img draw.Image, cover image.Image, pix []byte, stride, size int) {
+	if cover != nil {
+		draw.Draw(img, img.Bounds(), cover, cover.Bounds().Min, draw.Src)
+		return
+	}
+
+	opaque(pix, stride, size)
+}
+
+// newYCbCr returns copy of cover as YCbCr image. YCbCr covers keep their chroma subsampling,
+// other covers are converted without subsampling
+func newYCbCr(cover image.Image, rect image.Rectangle) *image.YCbCr {
+	if src, ok := cover.(*image.YCbCr); ok {
+		img := *src
+		img.Y = append([]byte{}, src.Y...)
+		img.Cb = append([]byte{}, src.Cb...)
+		img.Cr = append([]byte{}, src.Cr...)
+		return &img
+	}
+
+	img := image.NewYCbCr(rect, image.YCbCrSubsampleRatio444)
+
+	if cover == nil {
+		fill(img.Y, 0x80)
+		fill(img.Cb, 0x80)
+		fill(img.Cr, 0x80)
+		return img
+	}
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := color.YCbCrModel.Convert(cover.At(x, y)).(color.YCbCr)
+			img.Y[img.YOffset(x, y)] = c.Y
+			img.Cb[img.COffset(x, y)] = c.Cb
+			img.Cr[img.COffset(x, y)] = c.Cr
+		}
+	}
+
+	return img
+}
+
+// isOpaque reports whether every pixel of img is opaque
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface {
+		Opaque() bool
+	}); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// newGray returns copy of cover as grayscale image for gray codec prw. Codecs storing more than 8
+// bits per point and 16-bit grayscale covers need 16-bit luminance. If cover is nil, the image of
+// size rect is black
+func newGray(prw imgio.PointReadWriter, cover image.Image, rect image.Rectangle) draw.Image {
+	var img draw.Image = image.NewGray(rect)
+	if _, ok := cover.(*image.Gray16); ok || prw.Bits(rect.Min) > 8 {
+		img = image.NewGray16(rect)
+	}
+
+	if cover != nil {
+		draw.Draw(img, img.Bounds(), cover, cover.Bounds().Min, draw.Src)
+	}
+
+	return img
+}
+
+// isAlphaChannelBits reports whether prw stores bits in alpha channel, such codec needs
+// non-premultiplied carrier
+func isAlphaChannelBits(prw imgio.PointReadWriter) bool {
+	codec, params := imgio.CodecOf(prw)
+	return codec == imgio.CodecChannelBits && imgio.ChannelMask(params)&imgio.ChannelA != 0
+}
+
+// isWideChannelBits reports whether prw uses 16 bit channels
+func isWideChannelBits(prw imgio.PointReadWriter) bool {
+	codec, params := imgio.CodecOf(prw)
+	return codec == imgio.CodecChannelBits && params>>8 > 8
+}
+
+func fill(p []byte, v byte) {
+	for i := range p {
+		p[i] = v
+	}
+}
+
+// opaque sets alpha of every pixel of pix with stride bytes per pixel and size bytes per channel
+func opaque(pix []byte, stride, size int) {
+	for i := stride - size; i < len(pix); i += stride {
+		fill(pix[i:i+size], 0xff)
+	}
+}
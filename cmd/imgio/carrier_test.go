@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/rand"
+	"image"
+	"io"
+	"testing"
+
+	"github.com/ivan1993spb/imgio"
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_newGray(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 3)
+	gray16 := image.NewGray16(rect)
+	_, err := rand.Read(gray16.Pix)
+	require.Nil(t, err)
+	grayBits12, err := imgio.NewGrayBitsReadWriter(12)
+	require.Nil(t, err)
+
+	tests := []struct {
+		prw   imgio.PointReadWriter
+		cover image.Image
+
+		expectedImage image.Image
+	}{
+		{imgio.GrayLSBReadWriter{}, nil, image.NewGray(rect)},
+		{grayBits12, nil, image.NewGray16(rect)},
+		{imgio.GrayLSBReadWriter{}, gray16, gray16},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expectedImage, newGray(test.prw, test.cover, rect), "Test index %d", i)
+	}
+}
+
+func Test_newCarrier_SmartPoint8GrayCover(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 3)
+	cover := image.NewGray(rect)
+	_, err := rand.Read(cover.Pix)
+	require.Nil(t, err)
+
+	img, storage, err := newCarrier(imgio.SmartPoint8ReadWriter{}, cover, rect)
+	require.Nil(t, err)
+	require.IsType(t, &image.RGBA{}, img)
+
+	data := make([]byte, 12)
+	_, err = rand.Read(data)
+	require.Nil(t, err)
+	_, err = storage.Write(data)
+	require.Nil(t, err)
+
+	actual := make([]byte, len(data))
+	_, err = io.ReadFull(imgio.NewPointStorage(imgio.NewImageAccessor(img, imgio.SmartPoint8ReadWriter{}),
+		imgio.NewSimplePointsSequenceGenerator(rect)), actual)
+	require.Nil(t, err)
+	require.Equal(t, data, actual)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io/ioutil"
+)
+
+// cover is an image which payload is embedded into
+type cover struct {
+	img    image.Image
+	format string
+	data   []byte
+}
+
+// loadCover reads and decodes cover image from file path
+func loadCover(path string) (*cover, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &cover{
+		img:    img,
+		format: format,
+		data:   data,
+	}, nil
+}
+
+// keepMetadata copies metadata of the cover into encoded image data of format. Only metadata of
+// PNG and JPEG covers is kept and only if the output has the same format
+func (c *cover) keepMetadata(format string, data []byte) []byte {
+	if c == nil || c.format != format {
+		return data
+	}
+
+	switch format {
+	case "png":
+		return insertPNGChunks(data, pngMetadata(c.data))
+	case "jpeg":
+		return insertJPEGSegments(data, jpegMetadata(c.data))
+	}
+
+	return data
+}
+
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// pngMetadataChunks are ancillary PNG chunks which don't depend on pixel encoding
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+	"pHYs": true,
+	"gAMA": true,
+	"cHRM": true,
+	"sRGB": true,
+	"iCCP": true,
+}
+
+// pngChunks calls f with type and raw bytes of every chunk of PNG data
+func pngChunks(data []byte, f func(typ string, chunk []byte)) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return
+	}
+
+	for i := len(pngSignature); i+12 <= len(data); {
+		size := int(binary.BigEndian.Uint32(data[i:]))
+		if size < 0 || i+12+size > len(data) {
+			return
+		}
+		f(string(data[i+4:i+8]), data[i:i+12+size])
+		i += 12 + size
+	}
+}
+
+// pngMetadata returns raw metadata chunks of PNG data
+func pngMetadata(data []byte) []byte {
+	var meta []byte
+	pngChunks(data, func(typ string, chunk []byte) {
+		if pngMetadataChunks[typ] {
+			meta = append(meta, chunk...)
+		}
+	})
+	return meta
+}
+
+// insertPNGChunks inserts raw chunks right after IHDR chunk of PNG data
+func insertPNGChunks(data, chunks []byte) []byte {
+	if len(chunks) == 0 {
+		return data
+	}
+
+	end := -1
+	offset := len(pngSignature)
+	pngChunks(data, func(typ string, chunk []byte) {
+		if typ == "IHDR" {
+			end = offset + len(chunk)
+		}
+		offset += len(chunk)
+	})
+	if end < 0 {
+		return data
+	}
+
+	out := make([]byte, 0, len(data)+len(chunks))
+	out = append(out, data[:end]...)
+	out = append(out, chunks...)
+	return append(out, data[end:]...)
+}
+
+const (
+	jpegMarkerSOI  = 0xd8
+	jpegMarkerSOS  = 0xda
+	jpegMarkerAPP1 = 0xe1
+	jpegMarkerCOM  = 0xfe
+)
+
+// jpegAPP1Metadata are identifiers of APP1 segments which keep EXIF and XMP metadata
+var jpegAPP1Metadata = [][]byte{
+	[]byte("Exif\x00\x00"),
+	[]byte("http://ns.adobe.com/xap/1.0/\x00"),
+	[]byte("http://ns.adobe.com/xmp/extension/\x00"),
+}
+
+// isJPEGMetadata reports whether segment with marker and payload keeps EXIF, XMP or a comment.
+// Other segments such as APP0 (JFIF) and APP14 (Adobe) describe encoding of the cover and would
+// make decoders misinterpret the encoded carrier
+func isJPEGMetadata(marker byte, payload []byte) bool {
+	if marker == jpegMarkerCOM {
+		return true
+	}
+	if marker != jpegMarkerAPP1 {
+		return false
+	}
+	for _, id := range jpegAPP1Metadata {
+		if bytes.HasPrefix(payload, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// jpegMetadata returns raw EXIF and XMP APP1 segments and COM segments of JPEG data
+func jpegMetadata(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0xff || data[1] != jpegMarkerSOI {
+		return nil
+	}
+
+	var meta []byte
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		if marker == jpegMarkerSOS {
+			break
+		}
+
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			break
+		}
+
+		if isJPEGMetadata(marker, data[i+4:i+2+size]) {
+			meta = append(meta, data[i:i+2+size]...)
+		}
+		i += 2 + size
+	}
+
+	return meta
+}
+
+// insertJPEGSegments inserts raw segments right after SOI marker of JPEG data
+func insertJPEGSegments(data, segments []byte) []byte {
+	if len(segments) == 0 || len(data) < 2 {
+		return data
+	}
+
+	out := make([]byte, 0, len(data)+len(segments))
+	out = append(out, data[:2]...)
+	out = append(out, segments...)
+	return append(out, data[2:]...)
+}
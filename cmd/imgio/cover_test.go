@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ivan1993spb/imgio"
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+// jpegSegment returns raw JPEG segment with marker and payload
+func jpegSegment(marker byte, payload string) []byte {
+	segment := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func Test_jpegMetadata(t *testing.T) {
+	exif := jpegSegment(jpegMarkerAPP1, "Exif\x00\x00MM")
+	xmp := jpegSegment(jpegMarkerAPP1, "http://ns.adobe.com/xap/1.0/\x00<x/>")
+	comment := jpegSegment(jpegMarkerCOM, "comment")
+
+	data := []byte{0xff, jpegMarkerSOI}
+	for _, segment := range [][]byte{
+		jpegSegment(0xe0, "JFIF\x00\x01\x01"),
+		exif,
+		jpegSegment(jpegMarkerAPP1, "unknown"),
+		xmp,
+		jpegSegment(0xee, "Adobe\x00\x64\x00\x00\x00\x00\x00"),
+		comment,
+		jpegSegment(jpegMarkerSOS, ""),
+	} {
+		data = append(data, segment...)
+	}
+
+	expected := append(append(append([]byte{}, exif...), xmp...), comment...)
+	require.Equal(t, expected, jpegMetadata(data))
+}
+
+func Test_cover_keepMetadata_JPEGAdobeTransform(t *testing.T) {
+	rect := image.Rect(0, 0, 64, 48)
+	buff := bytes.NewBuffer(nil)
+	require.Nil(t, jpeg.Encode(buff, noiseImage(rect), nil))
+
+	// Transform 0 of APP14 segment tells decoders that components are RGB, not YCbCr
+	data := buff.Bytes()
+	data = insertJPEGSegments(data, jpegSegment(0xee, "Adobe\x00\x64\x00\x00\x00\x00\x00"))
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	require.Nil(t, err)
+	cov := &cover{img: img, format: format, data: data}
+
+	prw, err := imgio.CodecReadWriter(imgio.CodecYCbCrLuma, 0)
+	require.Nil(t, err)
+	e := &encoder{prw: prw, codec: imgio.CodecYCbCrLuma}
+
+	payload := make([]byte, 100)
+	_, err = rand.Read(payload)
+	require.Nil(t, err)
+
+	out, format, err := e.encode(cov, payload, 0)
+	require.Nil(t, err)
+	require.Equal(t, "jpeg", format)
+
+	fr, err := openPayload(out)
+	require.Nil(t, err)
+	actual, err := ioutil.ReadAll(fr)
+	require.Nil(t, err)
+	require.Equal(t, payload, actual)
+}
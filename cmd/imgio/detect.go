@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+
+	"github.com/ivan1993spb/imgio"
+)
+
+var (
+	errNoPayload    = errors.New("no payload found in image")
+	errPaletteImage = errors.New("paletted images can't carry payload: palette quantizes written colors")
+)
+
+type codecParams struct {
+	codec  imgio.CodecID
+	params uint16
+}
+
+// pointCodecs returns codecs which may have been used to write payload into image of generic type
+func pointCodecs() []codecParams {
+	codecs := []codecParams{
+		{imgio.CodecSimplePoint32, 0},
+		{imgio.CodecSimplePoint64, 0},
+		{imgio.CodecGentlePoint16, 0},
+		{imgio.CodecSmartPoint8, 0},
+		{imgio.CodecNRGBAPoint32, 0},
+		{imgio.CodecNRGBAPoint64, 0},
+		{imgio.CodecGrayLSB, 0},
+	}
+
+	for bits := imgio.ChannelBitsMin; bits <= imgio.ChannelBitsMax; bits++ {
+		codecs = append(codecs, codecParams{imgio.CodecGrayBits, uint16(bits)})
+	}
+
+	for bits := imgio.ChannelBitsMin; bits <= imgio.ChannelBitsMax; bits++ {
+		for mask := imgio.ChannelR; mask <= imgio.ChannelRGBA; mask++ {
+			codecs = append(codecs, codecParams{imgio.CodecChannelBits, uint16(bits)<<8 | uint16(mask)})
+		}
+	}
+
+	return codecs
+}
+
+// openPayload detects format of image data and codec of payload written with simple points
+// sequence generator and returns frame reader of the payload
+func openPayload(data []byte) (*imgio.FrameReader, error) {
+	return openPayloadGen(data, generators["simple"], nil)
+}
+
+// openPayloadGen detects format of image data and codec of payload written with points sequence
+// generator newGen with key and returns frame reader of the payload
+func openPayloadGen(data []byte, newGen func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator,
+	key []byte) (*imgio.FrameReader, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unknown image format: %s", err)
+	}
+
+	switch format {
+	case "gif":
+		return nil, errPaletteImage
+	case "jpeg":
+		// Baseline JPEG may carry payload in DCT coefficients
+		if coeffs, err := imgio.DecodeJPEGCoefficients(bytes.NewReader(data)); err == nil {
+			jrw := imgio.NewJPEGReadWriter(coeffs, newGen(coeffs.Bounds(), key))
+			if fr, ok := tryFrame(jrw, codecParams{imgio.CodecJSteg, 0}); ok {
+				return fr, nil
+			}
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return openImagePayload(img, newGen, key)
+}
+
+// openImagePayload picks read-writer of decoded image img and returns frame reader of payload
+// written with points sequence generator newGen with key
+func openImagePayload(img image.Image, newGen func(rect image.Rectangle, key []byte) imgio.PointsSequenceGenerator,
+	key []byte) (*imgio.FrameReader, error) {
+	gen := func() imgio.PointsSequenceGenerator {
+		return newGen(img.Bounds(), key)
+	}
+
+	switch img := img.(type) {
+	case *image.Paletted:
+		return nil, errPaletteImage
+
+	case *image.YCbCr:
+		for _, codec := range []codecParams{{imgio.CodecYCbCrSimple, 0}, {imgio.CodecYCbCrLuma, 0}} {
+			prw, err := imgio.CodecReadWriter(codec.codec, codec.params)
+			if err != nil {
+				return nil, err
+			}
+			storage := imgio.NewPointStorage(imgio.NewYCbCrAccessor(img, prw.(imgio.PointReadWriterYCbCr)), gen())
+			if fr, ok := tryFrame(storage, codec); ok {
+				return fr, nil
+			}
+		}
+
+	case draw.Image:
+		if rgba, ok := img.(*image.RGBA); ok {
+			storage := imgio.NewPointStorage(imgio.NewRGBAAccessor(rgba, imgio.PointReadWriterRGBASimple{}), gen())
+			if fr, ok := tryFrame(storage, codecParams{imgio.CodecRGBASimple, 0}); ok {
+				return fr, nil
+			}
+		}
+
+		for _, codec := range pointCodecs() {
+			prw, err := imgio.CodecReadWriter(codec.codec, codec.params)
+			if err != nil {
+				continue
+			}
+			storage := imgio.NewPointStorage(imgio.NewImageAccessor(img, prw.(imgio.PointReadWriter)), gen())
+			if fr, ok := tryFrame(storage, codec); ok {
+				return fr, nil
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported image type %T", img)
+	}
+
+	return nil, errNoPayload
+}
+
+// tryFrame returns frame reader of storage if the storage contains frame written with codec
+func tryFrame(storage io.Reader, codec codecParams) (*imgio.FrameReader, bool) {
+	fr, err := imgio.NewFrameReader(storage)
+	if err != nil {
+		return nil, false
+	}
+
+	header := fr.Header()
+	if header.Codec != codec.codec {
+		return nil, false
+	}
+	// Parameters of YCbCr simple codec don't affect reading
+	if codec.codec != imgio.CodecYCbCrSimple && header.CodecParams != codec.params {
+		return nil, false
+	}
+
+	return fr, true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ivan1993spb/imgio"
+)
+
+// payloadFlags returns frame flags for passphrase and compression options
+func payloadFlags(passphrase string, compress bool) uint8 {
+	var flags uint8
+	if passphrase != "" {
+		flags |= imgio.FrameFlagEncrypted
+	}
+	if compress {
+		flags |= imgio.FrameFlagCompressed
+	}
+	return flags
+}
+
+// preparePayload reads payload from r and transforms it for embedding according to flags. The
+// payload is transformed entirely before writing, so that capacity of carrier is known before
+// the carrier is touched. It returns number of bytes read from r too
+func preparePayload(r io.Reader, flags uint8, passphrase string) ([]byte, int64, error) {
+	buff := bytes.NewBuffer(nil)
+	w := imgio.NewPayloadWriter(buff, flags, []byte(passphrase))
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return nil, n, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, n, err
+	}
+
+	return buff.Bytes(), n, nil
+}
+
+// frameSize returns number of bytes which are needed to store payload in a frame
+func frameSize(payload []byte) int64 {
+	return int64(imgio.FrameHeaderSize + len(payload))
+}
+
+// writeFrame writes prepared payload into storage in a frame of codec. It returns error without
+// writing anything if capacity of storage is too small
+func writeFrame(storage imgio.Storage, codec imgio.CodecID, params uint16, flags uint8, payload []byte) error {
+	capacity, err := storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := storage.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if size := frameSize(payload); size > capacity {
+		return fmt.Errorf("payload needs %d bytes, capacity of carrier is %d bytes", size, capacity)
+	}
+
+	fw, err := imgio.NewFrameWriter(storage, codec, params, flags)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(payload); err != nil {
+		return err
+	}
+
+	return fw.Close()
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"log"
+
+	"github.com/ivan1993spb/imgio"
+	"github.com/urfave/cli"
+)
+
+// encoder embeds frames into cover images or generated carriers
+type encoder struct {
+	prw    interface{}
+	codec  imgio.CodecID
+	params uint16
+
+	// format is output format, if it is empty the format is chosen for every cover
+	format string
+	force  bool
+	warned bool
+
+	// aspect and fill describe generated carriers
+	aspect float64
+	fill   func(rect image.Rectangle) image.Image
+}
+
+// newEncoder returns encoder configured with flags of encode command
+func newEncoder(c *cli.Context) (*encoder, error) {
+	codec, params, prw, err := parseCodec(c, c.String("codec"))
+	if err != nil {
+		return nil, err
+	}
+	if prw == nil {
+		return nil, fmt.Errorf("codec %s is written by encode_jpeg command", codec)
+	}
+	if len(codecFormats(codec, params)) == 0 {
+		return nil, fmt.Errorf("codec %s isn't kept by any output format", codec)
+	}
+
+	aspect, err := parseAspect(c.String("aspect"))
+	if err != nil {
+		return nil, err
+	}
+
+	fill, ok := backgrounds[c.String("fill")]
+	if !ok {
+		return nil, fmt.Errorf("unknown fill %q", c.String("fill"))
+	}
+
+	return &encoder{
+		prw:    prw,
+		codec:  codec,
+		params: params,
+		format: c.String("format"),
+		force:  c.Bool("force"),
+		aspect: aspect,
+		fill:   fill,
+	}, nil
+}
+
+// parseCodec returns codec with name, its parameters and point read-writer. Parameters of
+// channel-bits codec are taken from bits and channels flags, parameters of gray-bits codec are
+// taken from bits flag. CMYK and NYCbCrA codecs are refused, no output format keeps their planes
+func parseCodec(c *cli.Context, name string) (imgio.CodecID, uint16, interface{}, error) {
+	codec, err := imgio.ParseCodecID(name)
+	if err != nil {
+		return codec, 0, nil, err
+	}
+	switch codec {
+	case imgio.CodecCMYKBits, imgio.CodecNYCbCrABits:
+		return codec, 0, nil, fmt.Errorf("codec %s is available only in the library", codec)
+	}
+
+	var params uint16
+	if codec == imgio.CodecChannelBits {
+		mask, err := parseChannels(c.String("channels"))
+		if err != nil {
+			return codec, 0, nil, err
+		}
+		params = uint16(c.Int("bits"))<<8 | uint16(mask)
+	}
+	if codec == imgio.CodecGrayBits {
+		params = uint16(c.Int("bits"))
+	}
+
+	if codec == imgio.CodecJSteg {
+		return codec, params, nil, nil
+	}
+
+	prw, err := imgio.CodecReadWriter(codec, params)
+	return codec, params, prw, err
+}
+
+// chooseFormat returns output format for cover, cov may be nil. Format of the cover is preferred
+// if it keeps the payload
+func (e *encoder) chooseFormat(cov *cover) (string, error) {
+	formats := coverFormats(cov, e.prw, codecFormats(e.codec, e.params))
+
+	format := e.format
+	if format == "" {
+		if len(formats) == 0 {
+			return "", fmt.Errorf("codec %s isn't kept by any output format", e.codec)
+		}
+		format = formats[0]
+		if cov != nil && contains(formats, cov.format) {
+			format = cov.format
+		}
+	}
+
+	if err := checkFormat(format, e.codec, formats); err != nil {
+		if _, ok := outputFormats[format]; !ok || !e.force {
+			return "", err
+		}
+		if !e.warned {
+			log.Println("warning:", err)
+			e.warned = true
+		}
+	}
+
+	return format, nil
+}
+
+// capacity returns number of payload bytes which a frame in cover can keep
+func (e *encoder) capacity(cov *cover) (int64, error) {
+	_, storage, err := newCarrier(e.prw, cov.img, cov.img.Bounds())
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	return size - int64(imgio.FrameHeaderSize), nil
+}
+
+// encode writes payload in a frame with flags into cover and returns encoded image data. If cov
+// is nil, carrier of the smallest size is generated
+func (e *encoder) encode(cov *cover, payload []byte, flags uint8) ([]byte, string, error) {
+	format, err := e.chooseFormat(cov)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		background image.Image
+		rect       image.Rectangle
+	)
+	if cov != nil {
+		background = cov.img
+	} else {
+		if rect, err = carrierRect(e.prw, frameSize(payload), e.aspect); err != nil {
+			return nil, "", err
+		}
+		background = e.fill(rect)
+	}
+
+	img, storage, err := newCarrier(e.prw, background, rect)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := writeFrame(storage, e.codec, e.params, flags, payload); err != nil {
+		return nil, "", err
+	}
+
+	buff := bytes.NewBuffer(nil)
+	if err := outputFormats[format](buff, img); err != nil {
+		return nil, "", err
+	}
+
+	return cov.keepMetadata(format, buff.Bytes()), format, nil
+}
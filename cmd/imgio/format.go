@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/ivan1993spb/imgio"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// outputFormats are image formats which encode command can write
+var outputFormats = map[string]func(w io.Writer, img image.Image) error{
+	"png": png.Encode,
+	"bmp": bmp.Encode,
+	"tiff": func(w io.Writer, img image.Image) error {
+		return tiff.Encode(w, img, &tiff.Options{Compression: tiff.Deflate})
+	},
+	"gif": func(w io.Writer, img image.Image) error {
+		return gif.Encode(w, img, nil)
+	},
+	"jpeg": func(w io.Writer, img image.Image) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 100})
+	},
+}
+
+// codecFormats returns formats which keep payload written with codec, the first one is the
+// default. PNG and BMP store colors unpremultiplied, so codecs which change alpha survive only
+// in TIFF which keeps premultiplied colors as is. BMP keeps only 8 bits per channel. Luma codec
+// tolerates JPEG compression in the most cases and requires its YCbCr planes on decoding, while
+// chroma of YCbCr simple codec is lost by JPEG and by conversion of other encoders to RGB. NRGBA
+// codecs and channel bits codecs with alpha write unpremultiplied colors, so they survive PNG too.
+// Gray codecs need grayscale PNG or TIFF, BMP decodes grayscale images as paletted ones
+func codecFormats(codec imgio.CodecID, params uint16) []string {
+	switch codec {
+	case imgio.CodecYCbCrSimple:
+		return nil
+	case imgio.CodecNRGBAPoint32, imgio.CodecNRGBAPoint64, imgio.CodecGrayLSB, imgio.CodecGrayBits:
+		return []string{"png", "tiff"}
+	case imgio.CodecSmartPoint8:
+		return []string{"png", "tiff", "bmp"}
+	case imgio.CodecChannelBits:
+		bits, mask := int(params>>8), imgio.ChannelMask(params)
+		if mask&imgio.ChannelA != 0 || bits > 8 {
+			return []string{"png", "tiff"}
+		}
+		return []string{"png", "tiff", "bmp"}
+	case imgio.CodecYCbCrLuma:
+		return []string{"jpeg"}
+	}
+	return []string{"tiff"}
+}
+
+// coverFormats returns formats of codecFormats which keep payload written into cover with point
+// read-writer prw. Colors of translucent pixels of RGBA carriers are changed by PNG and BMP, NRGBA
+// carriers keep them and gray carriers have no alpha
+func coverFormats(cov *cover, prw interface{}, formats []string) []string {
+	if _, ok := prw.(imgio.PointReadWriterYCbCr); ok || cov == nil || isOpaque(cov.img) {
+		return formats
+	}
+	switch codec, _ := imgio.CodecOf(prw); codec {
+	case imgio.CodecNRGBAPoint32, imgio.CodecNRGBAPoint64, imgio.CodecGrayLSB, imgio.CodecGrayBits:
+		return formats
+	}
+	if prw, ok := prw.(imgio.PointReadWriter); ok && isAlphaChannelBits(prw) {
+		return formats
+	}
+
+	var safe []string
+	for _, format := range formats {
+		if format == "tiff" {
+			safe = append(safe, format)
+		}
+	}
+	return safe
+}
+
+// checkFormat returns error if payload written with codec doesn't survive format. Formats are
+// ones which keep the payload
+func checkFormat(format string, codec imgio.CodecID, formats []string) error {
+	if _, ok := outputFormats[format]; !ok {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if contains(formats, format) {
+		return nil
+	}
+
+	return fmt.Errorf("format %s corrupts payload written with codec %s, use one of: %s",
+		format, codec, strings.Join(formats, ", "))
+}
+
+// parseChannels parses channel mask like "rgb" or "ra"
+func parseChannels(s string) (imgio.ChannelMask, error) {
+	var mask imgio.ChannelMask
+	for _, c := range strings.ToLower(s) {
+		switch c {
+		case 'r':
+			mask |= imgio.ChannelR
+		case 'g':
+			mask |= imgio.ChannelG
+		case 'b':
+			mask |= imgio.ChannelB
+		case 'a':
+			mask |= imgio.ChannelA
+		default:
+			return 0, fmt.Errorf("unknown channel %q", c)
+		}
+	}
+	return mask, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ivan1993spb/imgio"
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_codecFormats_RoundTrip(t *testing.T) {
+	codecs := []codecParams{
+		{imgio.CodecSimplePoint32, 0},
+		{imgio.CodecSimplePoint64, 0},
+		{imgio.CodecGentlePoint16, 0},
+		{imgio.CodecSmartPoint8, 0},
+		{imgio.CodecRGBASimple, 0},
+		{imgio.CodecYCbCrLuma, 0},
+		{imgio.CodecNRGBAPoint32, 0},
+		{imgio.CodecNRGBAPoint64, 0},
+		{imgio.CodecGrayLSB, 0},
+	}
+	for bits := imgio.ChannelBitsMin; bits <= imgio.ChannelBitsMax; bits++ {
+		for _, mask := range []imgio.ChannelMask{imgio.ChannelR, imgio.ChannelRGB, imgio.ChannelRGBA, imgio.ChannelG | imgio.ChannelA} {
+			codecs = append(codecs, codecParams{imgio.CodecChannelBits, uint16(bits)<<8 | uint16(mask)})
+		}
+		codecs = append(codecs, codecParams{imgio.CodecGrayBits, uint16(bits)})
+	}
+
+	payload := make([]byte, 300)
+	_, err := rand.Read(payload)
+	require.Nil(t, err)
+
+	for i, codec := range codecs {
+		prw, err := imgio.CodecReadWriter(codec.codec, codec.params)
+		require.Nil(t, err, "Test index %d", i)
+
+		for _, format := range codecFormats(codec.codec, codec.params) {
+			for fill := range backgrounds {
+				e := &encoder{
+					prw:    prw,
+					codec:  codec.codec,
+					params: codec.params,
+					format: format,
+					aspect: 1,
+					fill:   backgrounds[fill],
+				}
+
+				data, _, err := e.encode(nil, payload, 0)
+				require.Nil(t, err, "Test index %d: %s %s %s", i, codec.codec, format, fill)
+
+				fr, err := openPayload(data)
+				require.Nil(t, err, "Test index %d: %s %s %s", i, codec.codec, format, fill)
+				actual, err := ioutil.ReadAll(fr)
+				require.Nil(t, err, "Test index %d: %s %s %s", i, codec.codec, format, fill)
+				require.True(t, bytes.Equal(payload, actual), "Test index %d: %s %s %s", i, codec.codec, format, fill)
+			}
+		}
+	}
+}
+
+func Test_encoder_chooseFormat_NoFormats(t *testing.T) {
+	prw, err := imgio.CodecReadWriter(imgio.CodecYCbCrSimple, 0)
+	require.Nil(t, err)
+	require.Empty(t, codecFormats(imgio.CodecYCbCrSimple, 0))
+
+	e := &encoder{prw: prw, codec: imgio.CodecYCbCrSimple}
+	_, err = e.chooseFormat(nil)
+	require.NotNil(t, err)
+
+	e.format = "png"
+	_, err = e.chooseFormat(nil)
+	require.NotNil(t, err)
+}
@@ -2,23 +2,34 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"image"
-	"image/draw"
-	"image/gif"
+	_ "image/gif"
 	"image/jpeg"
-	"image/png"
+	_ "image/png"
 	"io"
-	"log"
+	"io/ioutil"
 	"os"
 	"sort"
 
 	"github.com/ivan1993spb/imgio"
 
 	"github.com/urfave/cli"
-	"golang.org/x/image/bmp"
-	"golang.org/x/image/tiff"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
 )
 
+var passphraseFlag = cli.StringFlag{
+	Name:   "passphrase",
+	Usage:  "encrypt or decrypt payload with passphrase",
+	EnvVar: "IMGIO_PASSPHRASE",
+}
+
+var compressFlag = cli.BoolFlag{
+	Name:  "compress",
+	Usage: "compress payload before embedding",
+}
+
 func main() {
 	app := cli.NewApp()
 
@@ -27,37 +38,179 @@ func main() {
 	app.Commands = []cli.Command{
 		{
 			Name: "encode",
+			Flags: []cli.Flag{
+				passphraseFlag,
+				compressFlag,
+				cli.StringFlag{Name: "codec", Value: imgio.CodecSimplePoint32.String(), Usage: "codec of payload"},
+				cli.IntFlag{Name: "bits", Value: 1, Usage: "bits per channel of channel-bits codec or per point of gray-bits codec"},
+				cli.StringFlag{Name: "channels", Value: "rgb", Usage: "channels of channel-bits codec"},
+				cli.StringFlag{Name: "format", Usage: "output format: png, bmp, tiff, gif or jpeg, by default the best one for codec"},
+				cli.BoolFlag{Name: "force", Usage: "write format which corrupts payload of codec"},
+				cli.StringFlag{Name: "cover", Usage: "path to cover image, by default carrier of the smallest size is generated"},
+				cli.StringFlag{Name: "aspect", Value: "1:1", Usage: "aspect ratio of generated carrier like 16:9 or 1.5"},
+				cli.StringFlag{Name: "fill", Value: "noise", Usage: "background of generated carrier: noise, gradient or blank"},
+				cli.StringFlag{Name: "out-dir", Usage: "split payload across several images written into directory"},
+				cli.StringFlag{Name: "cover-dir", Usage: "directory of cover images for split payload, by default carriers are generated"},
+				cli.IntFlag{Name: "part-size", Value: 1 << 20, Usage: "bytes of split payload per generated carrier"},
+			},
+			Action: func(c *cli.Context) error {
+				e, err := newEncoder(c)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				var cov *cover
+				if path := c.String("cover"); path != "" {
+					if cov, err = loadCover(path); err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+				}
+
+				// Format is checked before payload is read
+				if _, err := e.chooseFormat(cov); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				passphrase := c.String("passphrase")
+				flags := payloadFlags(passphrase, c.Bool("compress"))
+
+				payload, _, err := preparePayload(os.Stdin, flags, passphrase)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				if outDir := c.String("out-dir"); outDir != "" {
+					parts, err := splitPayload(e, payload, c.String("cover-dir"), c.Int("part-size"))
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					if err := writeParts(e, parts, flags, outDir); err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					return nil
+				}
+
+				data, _, err := e.encode(cov, payload, flags)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				if _, err := os.Stdout.Write(data); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "decode",
+			Usage:     "decode payload of image from stdin or of image set split by encode --out-dir",
+			ArgsUsage: "[image or directory...]",
+			Flags:     []cli.Flag{passphraseFlag},
 			Action: func(c *cli.Context) error {
-				img := imgio.NewImage(
-					image.NewRGBA(image.Rect(0, 0, 10, 10)),
-					imgio.NewSimplePointsSequenceGenerator(image.Rect(0, 0, 10, 10)),
-					imgio.SimplePoint32ReadWriter{},
-				)
-				n, err := io.Copy(img, os.Stdin)
-				log.Println(n, err)
-
-				err = jpeg.Encode(os.Stdout, img, &jpeg.Options{})
-				log.Println(err)
+				var frames []*imgio.FrameReader
+
+				if c.NArg() == 0 {
+					data, err := ioutil.ReadAll(os.Stdin)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+
+					fr, err := openPayload(data)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					frames = append(frames, fr)
+				} else {
+					paths, err := listFiles(c.Args()...)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+
+					for _, path := range paths {
+						data, err := ioutil.ReadFile(path)
+						if err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+
+						fr, err := openPayload(data)
+						if err != nil {
+							return cli.NewExitError(fmt.Sprintf("%s: %s", path, err), 1)
+						}
+						frames = append(frames, fr)
+					}
+				}
+
+				payload, flags, err := joinPayload(frames)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				passphrase := c.String("passphrase")
+				if flags&imgio.FrameFlagEncrypted != 0 && passphrase == "" {
+					return cli.NewExitError("payload is encrypted, passphrase is required", 1)
+				}
+				r := imgio.NewPayloadReader(payload, flags, []byte(passphrase))
+
+				if _, err := io.Copy(os.Stdout, r); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
 				return nil
 			},
 		},
+
 		{
-			Name: "decode",
+			Name:      "capacity",
+			Usage:     "report capacity of images and payload detected in them",
+			ArgsUsage: "[image or directory...]",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "codec", Usage: "codec of payload, by default codec of detected payload or simple32"},
+				cli.IntFlag{Name: "bits", Value: 1, Usage: "bits per channel of channel-bits codec or per point of gray-bits codec"},
+				cli.StringFlag{Name: "channels", Value: "rgb", Usage: "channels of channel-bits codec"},
+				cli.StringFlag{Name: "generator", Value: "simple", Usage: "points sequence generator: simple or rand"},
+				cli.StringFlag{Name: "key", Usage: "key of rand points sequence generator"},
+				cli.BoolFlag{Name: "json", Usage: "write report as JSON"},
+			},
 			Action: func(c *cli.Context) error {
-				i, err := jpeg.Decode(os.Stdin)
-				log.Println(err)
+				var reports []*capacityReport
 
-				cimg := image.NewRGBA(i.Bounds())
-				draw.Draw(cimg, i.Bounds(), i, image.Point{}, draw.Over)
+				if c.NArg() == 0 {
+					data, err := ioutil.ReadAll(os.Stdin)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
 
-				img := imgio.NewImage(
-					cimg,
-					imgio.NewSimplePointsSequenceGenerator(image.Rect(0, 0, 10, 10)),
-					imgio.SimplePoint32ReadWriter{},
-				)
-				n, err := io.Copy(os.Stdout, img)
-				log.Println()
-				log.Println(n, err)
+					report, err := analyzeCapacity(c, "-", data)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					reports = append(reports, report)
+				} else {
+					paths, err := listFiles(c.Args()...)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+
+					for _, path := range paths {
+						data, err := ioutil.ReadFile(path)
+						if err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+
+						report, err := analyzeCapacity(c, path, data)
+						if err != nil {
+							return cli.NewExitError(fmt.Sprintf("%s: %s", path, err), 1)
+						}
+						reports = append(reports, report)
+					}
+				}
+
+				write := writeCapacityText
+				if c.Bool("json") {
+					write = writeCapacityJSON
+				}
+				if err := write(os.Stdout, reports); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
 
 				return nil
 			},
@@ -66,72 +219,100 @@ func main() {
 		{
 			Name: "show",
 			Action: func(c *cli.Context) error {
-				buff := bytes.NewBuffer(nil)
-				n, err := buff.ReadFrom(os.Stdin)
-				log.Println("read", n, err)
-
-				var img image.Image
-				img, err = jpeg.Decode(buff)
-				show(img)
-				log.Printf("jpeg %#v %s\n", img, err)
-				img, err = png.Decode(buff)
-				show(img)
-				log.Printf("png %#v %s\n", img, err)
-				img, err = gif.Decode(buff)
-				show(img)
-				log.Printf("gif %#v %s\n", img, err)
-				img, err = bmp.Decode(buff)
-				show(img)
-				log.Printf("bmp %#v %s\n", img, err)
-				img, err = tiff.Decode(buff)
-				show(img)
-				log.Printf("tif %#v %s\nf", img, err)
+				data, err := ioutil.ReadAll(os.Stdin)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				config, format, err := image.DecodeConfig(bytes.NewReader(data))
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				img, _, err := image.Decode(bytes.NewReader(data))
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				fmt.Printf("format %s, size %dx%d, image %T\n", format, config.Width, config.Height, img)
 
 				return nil
 			},
 		},
 
 		{
-			Flags: []cli.Flag{&cli.UintFlag{Name: "test "}},
-			Name:  "encode_jpeg",
+			Name: "encode_jpeg",
+			Flags: []cli.Flag{
+				passphraseFlag,
+				compressFlag,
+				cli.IntFlag{Name: "width", Value: 256, Usage: "width of generated cover image"},
+				cli.IntFlag{Name: "height", Value: 256, Usage: "height of generated cover image"},
+				cli.IntFlag{Name: "quality", Value: 90, Usage: "JPEG quality of generated cover image"},
+				cli.StringFlag{Name: "cover", Usage: "path to baseline JPEG cover image, by default noise image is generated"},
+			},
 			Action: func(c *cli.Context) error {
-				log.Println(c.Uint("test"))
-				i := image.NewYCbCr(image.Rect(0, 0, 3, 3), image.YCbCrSubsampleRatio420)
-				// i.YStride = 16
-				// i.CStride = 8
-				imgrw := imgio.NewImageReadWriterYCbCr(
-					i,
-					imgio.NewSimplePointsSequenceGenerator(image.Rect(0, 0, 3, 3)),
-					imgio.PointReadWriterYCbCrSimple{uint8(c.Uint("test"))},
-				)
-				n, err := io.Copy(imgrw, os.Stdin)
-				log.Println(n, err)
-				log.Printf("%#v\n", i)
-
-				err = jpeg.Encode(os.Stdout, i, &jpeg.Options{Quality: 100})
-				log.Println(err)
+				var data []byte
+				if path := c.String("cover"); path != "" {
+					cover, err := ioutil.ReadFile(path)
+					if err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					data = cover
+				} else {
+					noise := noiseImage(image.Rect(0, 0, c.Int("width"), c.Int("height")))
+					buff := bytes.NewBuffer(nil)
+					if err := jpeg.Encode(buff, noise, &jpeg.Options{Quality: c.Int("quality")}); err != nil {
+						return cli.NewExitError(err.Error(), 1)
+					}
+					data = buff.Bytes()
+				}
+
+				coeffs, err := imgio.DecodeJPEGCoefficients(bytes.NewReader(data))
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				jrw := imgio.NewJPEGReadWriter(coeffs, imgio.NewSimplePointsSequenceGenerator(coeffs.Bounds()))
+
+				passphrase := c.String("passphrase")
+				flags := payloadFlags(passphrase, c.Bool("compress"))
+
+				payload, _, err := preparePayload(os.Stdin, flags, passphrase)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				if err := writeFrame(jrw, imgio.CodecJSteg, 0, flags, payload); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+
+				if err := jrw.Encode(os.Stdout); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
 				return nil
 			},
 		},
 		{
-			Name: "decode_jpeg",
+			Name:  "decode_jpeg",
+			Flags: []cli.Flag{passphraseFlag},
 			Action: func(c *cli.Context) error {
-				img, err := jpeg.Decode(os.Stdin)
-				log.Println(err)
+				coeffs, err := imgio.DecodeJPEGCoefficients(os.Stdin)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				jrw := imgio.NewJPEGReadWriter(coeffs, imgio.NewSimplePointsSequenceGenerator(coeffs.Bounds()))
 
-				if imgYCbCr, ok := img.(*image.YCbCr); ok {
-					imgrw := imgio.NewImageReadWriterYCbCr(
-						imgYCbCr,
-						imgio.NewSimplePointsSequenceGenerator(imgYCbCr.Rect),
-						imgio.PointReadWriterYCbCrSimple{},
-					)
-					log.Printf("%#v\n", imgYCbCr)
+				fr, err := imgio.NewFrameReader(jrw)
+				if err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
 
-					n, err := io.Copy(os.Stdout, imgrw)
-					log.Println(n, err)
+				passphrase := c.String("passphrase")
+				flags := fr.Header().Flags
+				if flags&imgio.FrameFlagEncrypted != 0 && passphrase == "" {
+					return cli.NewExitError("payload is encrypted, passphrase is required", 1)
+				}
 
-				} else {
-					log.Println("not ok =(")
+				if _, err := io.Copy(os.Stdout, imgio.NewPayloadReader(fr, flags, []byte(passphrase))); err != nil {
+					return cli.NewExitError(err.Error(), 1)
 				}
 
 				return nil
@@ -145,33 +326,4 @@ func main() {
 	app.Run(os.Args)
 }
 
-func show(img image.Image) {
-	switch img.(type) {
-	case *image.Alpha:
-		log.Println("Alpha")
-	case *image.Alpha16:
-		log.Println("Alpha16")
-	case *image.CMYK:
-		log.Println("CMYK")
-	case *image.Gray:
-		log.Println("Gray")
-	case *image.Gray16:
-		log.Println("Gray16")
-	case *image.NRGBA:
-		log.Println("NRGBA")
-	case *image.NRGBA64:
-		log.Println("NRGBA64")
-	case *image.NYCbCrA:
-		log.Println("NYCbCrA")
-	case *image.Paletted:
-		log.Println("Paletted")
-	case *image.RGBA:
-		log.Println("RGBA")
-	case *image.RGBA64:
-		log.Println("RGBA64")
-	case *image.YCbCr:
-		log.Println("YCbCr")
-	default:
-		log.Println("default")
-	}
-}
+// noiseImage returns image of size rect filled with random colors
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ivan1993spb/imgio"
+)
+
+var errPartSets = errors.New("images keep parts of different payloads")
+
+// part is a chunk of payload with cover it is embedded into, cov is nil for generated carriers
+type part struct {
+	cov   *cover
+	chunk []byte
+}
+
+// splitPayload splits payload across covers of directory coverDir in order of their names. If
+// coverDir is empty, payload is split into chunks of partSize bytes for generated carriers
+func splitPayload(e *encoder, payload []byte, coverDir string, partSize int) ([]part, error) {
+	var parts []part
+
+	if coverDir == "" {
+		if partSize <= 0 {
+			return nil, fmt.Errorf("invalid part size %d", partSize)
+		}
+		for len(parts) == 0 || len(payload) > 0 {
+			size := partSize
+			if size > len(payload) {
+				size = len(payload)
+			}
+			parts = append(parts, part{chunk: payload[:size]})
+			payload = payload[size:]
+		}
+		return parts, nil
+	}
+
+	paths, err := listFiles(coverDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if len(parts) > 0 && len(payload) == 0 {
+			break
+		}
+
+		cov, err := loadCover(path)
+		if err != nil {
+			log.Printf("skip %s: %s", path, err)
+			continue
+		}
+
+		capacity, err := e.capacity(cov)
+		if err != nil {
+			return nil, err
+		}
+		capacity -= imgio.PartManifestSize
+		if capacity <= 0 {
+			log.Printf("skip %s: cover is too small", path)
+			continue
+		}
+
+		size := len(payload)
+		if int64(size) > capacity {
+			size = int(capacity)
+		}
+		parts = append(parts, part{cov: cov, chunk: payload[:size]})
+		payload = payload[size:]
+	}
+
+	if len(parts) == 0 || len(payload) > 0 {
+		return nil, fmt.Errorf("covers of %s have not enough capacity, %d bytes remain", coverDir, len(payload))
+	}
+
+	return parts, nil
+}
+
+// writeParts embeds parts into images and writes them into directory outDir
+func writeParts(e *encoder, parts []part, flags uint8, outDir string) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	// Set ID must differ between runs, parts of different payloads are told apart only by it
+	var setID uint64
+	if err := binary.Read(rand.Reader, binary.BigEndian, &setID); err != nil {
+		return err
+	}
+
+	for i, p := range parts {
+		manifest, err := imgio.PartManifest{
+			SetID: setID,
+			Index: uint32(i),
+			Count: uint32(len(parts)),
+		}.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		data, format, err := e.encode(p.cov, append(manifest, p.chunk...), flags|imgio.FrameFlagPart)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(outDir, fmt.Sprintf("part%04d.%s", i, format))
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// joinPayload returns reader of payload of frames. If the frames keep parts of a split payload,
+// the parts are joined in order of their manifests. It returns frame flags of the payload
+func joinPayload(frames []*imgio.FrameReader) (io.Reader, uint8, error) {
+	if len(frames) == 0 {
+		return nil, 0, errNoPayload
+	}
+
+	flags := frames[0].Header().Flags
+	if flags&imgio.FrameFlagPart == 0 {
+		if len(frames) > 1 {
+			return nil, 0, fmt.Errorf("images keep %d different payloads", len(frames))
+		}
+		return frames[0], flags, nil
+	}
+
+	var setID uint64
+	var readers []io.Reader
+
+	for i, fr := range frames {
+		if fr.Header().Flags != flags {
+			return nil, 0, errPartSets
+		}
+
+		manifest, err := imgio.ReadPartManifest(fr)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if i == 0 {
+			if int(manifest.Count) > len(frames) {
+				return nil, 0, fmt.Errorf("%d of %d parts are given", len(frames), manifest.Count)
+			}
+			setID = manifest.SetID
+			readers = make([]io.Reader, manifest.Count)
+		}
+		if manifest.SetID != setID || int(manifest.Count) != len(readers) {
+			return nil, 0, errPartSets
+		}
+		if readers[manifest.Index] != nil {
+			return nil, 0, fmt.Errorf("part %d is duplicated", manifest.Index)
+		}
+
+		readers[manifest.Index] = fr
+	}
+
+	for i, r := range readers {
+		if r == nil {
+			return nil, 0, fmt.Errorf("part %d of %d is missing", i, len(readers))
+		}
+	}
+
+	return io.MultiReader(readers...), flags &^ imgio.FrameFlagPart, nil
+}
+
+// listFiles returns files of paths, directories are replaced with their files in order of names
+func listFiles(paths ...string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ivan1993spb/imgio"
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_writeParts_SetsDiffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imgio")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	prw, err := imgio.CodecReadWriter(imgio.CodecSimplePoint32, 0)
+	require.Nil(t, err)
+	e := &encoder{prw: prw, codec: imgio.CodecSimplePoint32, aspect: 1, fill: backgrounds["blank"]}
+
+	var frames []*imgio.FrameReader
+	for i, payload := range []string{"first payload", "other payload"} {
+		parts, err := splitPayload(e, []byte(payload), "", 7)
+		require.Nil(t, err, "Test index %d", i)
+		require.Len(t, parts, 2, "Test index %d", i)
+
+		outDir := filepath.Join(dir, payload)
+		require.Nil(t, writeParts(e, parts, 0, outDir), "Test index %d", i)
+
+		// Take the first part of the first payload and the second part of the other one
+		data, err := ioutil.ReadFile(filepath.Join(outDir, []string{"part0000.tiff", "part0001.tiff"}[i]))
+		require.Nil(t, err, "Test index %d", i)
+		fr, err := openPayload(data)
+		require.Nil(t, err, "Test index %d", i)
+		frames = append(frames, fr)
+	}
+
+	_, _, err = joinPayload(frames)
+	require.Equal(t, errPartSets, err)
+}
@@ -0,0 +1,141 @@
+package imgio
+
+import "errors"
+
+var ErrCodecUnknown = errors.New("Unknown codec")
+
+// CodecID identifies point read-writer which was used to store a payload
+type CodecID uint8
+
+const (
+	CodecUnknown CodecID = iota
+	CodecSimplePoint32
+	CodecSimplePoint64
+	CodecGentlePoint16
+	CodecSmartPoint8
+	CodecChannelBits
+	CodecRGBASimple
+	CodecYCbCrSimple
+	CodecYCbCrLuma
+	CodecJSteg
+	CodecNRGBAPoint32
+	CodecNRGBAPoint64
+	CodecGrayLSB
+	CodecGrayBits
+	// CMYK and NYCbCrA codecs are library-only, the imgio command refuses them because no output
+	// format keeps CMYK and NYCbCrA planes
+	CodecCMYKBits
+	CodecNYCbCrABits
+)
+
+var codecNames = map[CodecID]string{
+	CodecUnknown:       "unknown",
+	CodecSimplePoint32: "simple32",
+	CodecSimplePoint64: "simple64",
+	CodecGentlePoint16: "gentle16",
+	CodecSmartPoint8:   "smart8",
+	CodecChannelBits:   "channel-bits",
+	CodecRGBASimple:    "rgba-simple",
+	CodecYCbCrSimple:   "ycbcr-simple",
+	CodecYCbCrLuma:     "ycbcr-luma",
+	CodecJSteg:         "jsteg",
+	CodecNRGBAPoint32:  "nrgba32",
+	CodecNRGBAPoint64:  "nrgba64",
+	CodecGrayLSB:       "gray-lsb",
+	CodecGrayBits:      "gray-bits",
+	CodecCMYKBits:      "cmyk-bits",
+	CodecNYCbCrABits:   "nycbcra-bits",
+}
+
+func (id CodecID) String() string {
+	if name, ok := codecNames[id]; ok {
+		return name
+	}
+	return codecNames[CodecUnknown]
+}
+
+// ParseCodecID returns codec identifier by its name
+func ParseCodecID(name string) (CodecID, error) {
+	for id, codecName := range codecNames {
+		if id != CodecUnknown && codecName == name {
+			return id, nil
+		}
+	}
+	return CodecUnknown, ErrCodecUnknown
+}
+
+// CodecOf returns identifier and parameters of point read-writer prw. Parameters of
+// ChannelBitsReadWriter are packed as bits per channel in the high byte and channel mask in the
+// low byte. Parameters of GrayBitsReadWriter, PointReadWriterCMYKBits and
+// PointReadWriterNYCbCrABits are number of bits per channel
+func CodecOf(prw interface{}) (CodecID, uint16) {
+	switch prw := prw.(type) {
+	case SimplePoint32ReadWriter:
+		return CodecSimplePoint32, 0
+	case SimplePoint64ReadWriter:
+		return CodecSimplePoint64, 0
+	case GentlePoint16ReadWriter:
+		return CodecGentlePoint16, 0
+	case SmartPoint8ReadWriter:
+		return CodecSmartPoint8, 0
+	case ChannelBitsReadWriter:
+		return CodecChannelBits, uint16(prw.bits)<<8 | uint16(prw.mask)
+	case PointReadWriterRGBASimple:
+		return CodecRGBASimple, 0
+	case PointReadWriterYCbCrSimple:
+		return CodecYCbCrSimple, uint16(prw.Y)
+	case PointReadWriterYCbCrLuma:
+		return CodecYCbCrLuma, 0
+	case *JPEGReadWriter:
+		return CodecJSteg, 0
+	case NRGBAPoint32ReadWriter:
+		return CodecNRGBAPoint32, 0
+	case NRGBAPoint64ReadWriter:
+		return CodecNRGBAPoint64, 0
+	case GrayLSBReadWriter:
+		return CodecGrayLSB, 0
+	case GrayBitsReadWriter:
+		return CodecGrayBits, uint16(prw.bits)
+	case PointReadWriterCMYKBits:
+		return CodecCMYKBits, uint16(prw.bits)
+	case PointReadWriterNYCbCrABits:
+		return CodecNYCbCrABits, uint16(prw.bits)
+	}
+	return CodecUnknown, 0
+}
+
+// CodecReadWriter returns point read-writer identified by codec id and parameters params. Result
+// is PointReadWriter, PointReadWriterRGBA or PointReadWriterYCbCr depending on codec
+func CodecReadWriter(id CodecID, params uint16) (interface{}, error) {
+	switch id {
+	case CodecSimplePoint32:
+		return SimplePoint32ReadWriter{}, nil
+	case CodecSimplePoint64:
+		return SimplePoint64ReadWriter{}, nil
+	case CodecGentlePoint16:
+		return GentlePoint16ReadWriter{}, nil
+	case CodecSmartPoint8:
+		return SmartPoint8ReadWriter{}, nil
+	case CodecChannelBits:
+		return NewChannelBitsReadWriter(int(params>>8), ChannelMask(params))
+	case CodecRGBASimple:
+		return PointReadWriterRGBASimple{}, nil
+	case CodecYCbCrSimple:
+		return PointReadWriterYCbCrSimple{Y: uint8(params)}, nil
+	case CodecYCbCrLuma:
+		return PointReadWriterYCbCrLuma{}, nil
+	case CodecNRGBAPoint32:
+		return NRGBAPoint32ReadWriter{}, nil
+	case CodecNRGBAPoint64:
+		return NRGBAPoint64ReadWriter{}, nil
+	case CodecGrayLSB:
+		return GrayLSBReadWriter{}, nil
+	case CodecGrayBits:
+		return NewGrayBitsReadWriter(int(params))
+	case CodecCMYKBits:
+		return NewPointReadWriterCMYKBits(int(params))
+	case CodecNYCbCrABits:
+		return NewPointReadWriterNYCbCrABits(int(params))
+	}
+	return nil, ErrCodecUnknown
+}
@@ -0,0 +1,122 @@
+package imgio
+
+import (
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_CodecOf(t *testing.T) {
+	channelBits, err := NewChannelBitsReadWriter(3, ChannelRGB)
+	require.Nil(t, err)
+	grayBits, err := NewGrayBitsReadWriter(12)
+	require.Nil(t, err)
+	cmykBits, err := NewPointReadWriterCMYKBits(2)
+	require.Nil(t, err)
+	nycbcraBits, err := NewPointReadWriterNYCbCrABits(4)
+	require.Nil(t, err)
+
+	tests := []struct {
+		prw            interface{}
+		expectedCodec  CodecID
+		expectedParams uint16
+	}{
+		{SimplePoint32ReadWriter{}, CodecSimplePoint32, 0},
+		{SimplePoint64ReadWriter{}, CodecSimplePoint64, 0},
+		{GentlePoint16ReadWriter{}, CodecGentlePoint16, 0},
+		{SmartPoint8ReadWriter{}, CodecSmartPoint8, 0},
+		{channelBits, CodecChannelBits, 0x0300 | uint16(ChannelRGB)},
+		{PointReadWriterRGBASimple{}, CodecRGBASimple, 0},
+		{PointReadWriterYCbCrSimple{Y: 0x80}, CodecYCbCrSimple, 0x80},
+		{PointReadWriterYCbCrLuma{}, CodecYCbCrLuma, 0},
+		{&JPEGReadWriter{}, CodecJSteg, 0},
+		{NRGBAPoint32ReadWriter{}, CodecNRGBAPoint32, 0},
+		{NRGBAPoint64ReadWriter{}, CodecNRGBAPoint64, 0},
+		{GrayLSBReadWriter{}, CodecGrayLSB, 0},
+		{grayBits, CodecGrayBits, 12},
+		{cmykBits, CodecCMYKBits, 2},
+		{nycbcraBits, CodecNYCbCrABits, 4},
+		{nil, CodecUnknown, 0},
+	}
+
+	for i, test := range tests {
+		codec, params := CodecOf(test.prw)
+		require.Equal(t, test.expectedCodec, codec, "Test index %d", i)
+		require.Equal(t, test.expectedParams, params, "Test index %d", i)
+	}
+}
+
+func Test_CodecID_String(t *testing.T) {
+	require.Equal(t, "simple32", CodecSimplePoint32.String())
+	require.Equal(t, "unknown", CodecID(0xff).String())
+}
+
+func Test_CodecReadWriter(t *testing.T) {
+	channelBits, err := NewChannelBitsReadWriter(3, ChannelRGB)
+	require.Nil(t, err)
+	grayBits, err := NewGrayBitsReadWriter(12)
+	require.Nil(t, err)
+	cmykBits, err := NewPointReadWriterCMYKBits(2)
+	require.Nil(t, err)
+	nycbcraBits, err := NewPointReadWriterNYCbCrABits(4)
+	require.Nil(t, err)
+
+	prws := []interface{}{
+		SimplePoint32ReadWriter{},
+		SimplePoint64ReadWriter{},
+		GentlePoint16ReadWriter{},
+		SmartPoint8ReadWriter{},
+		channelBits,
+		PointReadWriterRGBASimple{},
+		PointReadWriterYCbCrSimple{Y: 0x80},
+		PointReadWriterYCbCrLuma{},
+		NRGBAPoint32ReadWriter{},
+		NRGBAPoint64ReadWriter{},
+		GrayLSBReadWriter{},
+		grayBits,
+		cmykBits,
+		nycbcraBits,
+	}
+
+	for i, prw := range prws {
+		actual, err := CodecReadWriter(CodecOf(prw))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, prw, actual, "Test index %d", i)
+	}
+
+	_, err = CodecReadWriter(CodecChannelBits, 0)
+	require.Equal(t, ErrChannelBits, err)
+	_, err = CodecReadWriter(CodecGrayBits, 17)
+	require.Equal(t, ErrChannelBits, err)
+	_, err = CodecReadWriter(CodecCMYKBits, 9)
+	require.Equal(t, ErrChannelBits, err)
+	_, err = CodecReadWriter(CodecJSteg, 0)
+	require.Equal(t, ErrCodecUnknown, err)
+	_, err = CodecReadWriter(CodecUnknown, 0)
+	require.Equal(t, ErrCodecUnknown, err)
+}
+
+func Test_ParseCodecID(t *testing.T) {
+	tests := []struct {
+		name string
+
+		expectedCodec CodecID
+		expectedErr   error
+	}{
+		{"simple32", CodecSimplePoint32, nil},
+		{"channel-bits", CodecChannelBits, nil},
+		{"ycbcr-luma", CodecYCbCrLuma, nil},
+		{"jsteg", CodecJSteg, nil},
+		{"nrgba64", CodecNRGBAPoint64, nil},
+		{"gray-bits", CodecGrayBits, nil},
+		{"cmyk-bits", CodecCMYKBits, nil},
+		{"unknown", CodecUnknown, ErrCodecUnknown},
+		{"", CodecUnknown, ErrCodecUnknown},
+	}
+
+	for i, test := range tests {
+		codec, err := ParseCodecID(test.name)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		require.Equal(t, test.expectedCodec, codec, "Test index %d", i)
+	}
+}
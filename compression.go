@@ -0,0 +1,22 @@
+package imgio
+
+import (
+	"compress/flate"
+	"io"
+)
+
+// CompressionLevel is level of flate compression of payloads
+const CompressionLevel = flate.BestCompression
+
+// NewCompressedWriter returns writer which compresses data with flate before writing to w. Close
+// must be called to flush pending data, it doesn't close w
+func NewCompressedWriter(w io.Writer) io.WriteCloser {
+	// flate.NewWriter fails only on invalid compression level
+	fw, _ := flate.NewWriter(w, CompressionLevel)
+	return fw
+}
+
+// NewCompressedReader returns reader which decompresses data read from r
+func NewCompressedReader(r io.Reader) io.ReadCloser {
+	return flate.NewReader(r)
+}
@@ -0,0 +1,44 @@
+package imgio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_Compression_RoundTrip(t *testing.T) {
+	payloads := [][]byte{
+		{},
+		[]byte("payload"),
+		bytes.Repeat([]byte("compressible payload "), 100),
+		randomBytes(t, 1000),
+	}
+
+	for i, payload := range payloads {
+		buff := bytes.NewBuffer(nil)
+
+		cw := NewCompressedWriter(buff)
+		_, err := writeChunks(cw, payload, 7)
+		require.Nil(t, err, "Test index %d", i)
+		require.Nil(t, cw.Close(), "Test index %d", i)
+
+		actual, err := ioutil.ReadAll(NewCompressedReader(buff))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, len(payload), len(actual), "Test index %d", i)
+		require.True(t, bytes.Equal(payload, actual), "Test index %d", i)
+	}
+}
+
+func Test_Compression_Ratio(t *testing.T) {
+	payload := bytes.Repeat([]byte("compressible payload "), 100)
+	buff := bytes.NewBuffer(nil)
+
+	cw := NewCompressedWriter(buff)
+	_, err := cw.Write(payload)
+	require.Nil(t, err)
+	require.Nil(t, cw.Close())
+
+	require.True(t, buff.Len() < len(payload)/10)
+}
@@ -0,0 +1,287 @@
+package imgio
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EncryptedChunkSize is maximal size of plaintext sealed in one chunk
+	EncryptedChunkSize = 4096
+
+	EncryptedSaltSize        = 16
+	EncryptedNoncePrefixSize = 8
+	EncryptedHeaderSize      = EncryptedSaltSize + EncryptedNoncePrefixSize
+
+	encryptedChunkHeaderSize = 4
+	encryptedChunkFinal      = 1 << 31
+
+	// Parameters of scrypt key derivation function
+	ScryptN = 1 << 15
+	ScryptR = 8
+	ScryptP = 1
+
+	encryptedKeySize = 32
+)
+
+const (
+	encryptedModeNone = iota
+	encryptedModeRead
+	encryptedModeWrite
+)
+
+var (
+	ErrEncryptedMode   = errors.New("Encrypted storage is not opened for this operation")
+	ErrEncryptedClosed = errors.New("Encrypted storage is closed")
+	ErrDecrypt         = errors.New("Message authentication failed")
+)
+
+// EncryptedStorage encrypts data with AES-256-GCM using key derived from passphrase with scrypt.
+// Data is sealed in chunks of EncryptedChunkSize bytes so that it can be streamed. Every chunk is
+// prefixed with its length and the flag of the last chunk, which are authenticated along with the
+// chunk, and the nonce of a chunk contains its sequence number, so reordered, truncated or
+// modified data is detected on reading. Encrypted data doesn't support seeking, Close must be
+// called after writing to seal the last chunk
+type EncryptedStorage struct {
+	r io.Reader
+	w io.Writer
+
+	passphrase []byte
+	aead       cipher.AEAD
+	nonce      []byte
+	counter    uint32
+
+	mode   int
+	buff   []byte
+	final  bool
+	closed bool
+
+	mux sync.Mutex
+}
+
+// NewEncryptedStorage returns encrypted storage which reads from and writes to storage
+func NewEncryptedStorage(storage io.ReadWriter, passphrase []byte) *EncryptedStorage {
+	return &EncryptedStorage{
+		r:          storage,
+		w:          storage,
+		passphrase: passphrase,
+	}
+}
+
+// NewEncryptedReader returns encrypted storage which only reads from r
+func NewEncryptedReader(r io.Reader, passphrase []byte) *EncryptedStorage {
+	return &EncryptedStorage{
+		r:          r,
+		passphrase: passphrase,
+	}
+}
+
+// NewEncryptedWriter returns encrypted storage which only writes to w
+func NewEncryptedWriter(w io.Writer, passphrase []byte) *EncryptedStorage {
+	return &EncryptedStorage{
+		w:          w,
+		passphrase: passphrase,
+	}
+}
+
+func (s *EncryptedStorage) init(salt, noncePrefix []byte) error {
+	key, err := scrypt.Key(s.passphrase, salt, ScryptN, ScryptR, ScryptP, encryptedKeySize)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	s.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	s.nonce = make([]byte, s.aead.NonceSize())
+	copy(s.nonce, noncePrefix)
+
+	return nil
+}
+
+func (s *EncryptedStorage) chunkNonce() []byte {
+	binary.BigEndian.PutUint32(s.nonce[EncryptedNoncePrefixSize:], s.counter)
+	s.counter++
+	return s.nonce
+}
+
+func (s *EncryptedStorage) writeHeader() error {
+	header := make([]byte, EncryptedHeaderSize)
+	if _, err := io.ReadFull(rand.Reader, header); err != nil {
+		return err
+	}
+
+	if err := s.init(header[:EncryptedSaltSize], header[EncryptedSaltSize:]); err != nil {
+		return err
+	}
+
+	_, err := s.w.Write(header)
+	return err
+}
+
+func (s *EncryptedStorage) writeChunk(final bool) error {
+	header := make([]byte, encryptedChunkHeaderSize, encryptedChunkHeaderSize+len(s.buff)+s.aead.Overhead())
+	length := uint32(len(s.buff))
+	if final {
+		length |= encryptedChunkFinal
+	}
+	binary.BigEndian.PutUint32(header, length)
+
+	chunk := s.aead.Seal(header, s.chunkNonce(), s.buff, header)
+	s.buff = s.buff[:0]
+
+	_, err := s.w.Write(chunk)
+	return err
+}
+
+// Write implements io.Writer interface
+func (s *EncryptedStorage) Write(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.w == nil || s.mode == encryptedModeRead {
+		return 0, ErrEncryptedMode
+	}
+
+	if s.closed {
+		return 0, ErrEncryptedClosed
+	}
+
+	if s.mode == encryptedModeNone {
+		if err := s.writeHeader(); err != nil {
+			return 0, err
+		}
+		s.mode = encryptedModeWrite
+		s.buff = make([]byte, 0, EncryptedChunkSize)
+	}
+
+	for n < len(p) {
+		// The chunk is sealed only when next data arrives, because the last chunk has to be
+		// marked as final on Close
+		if len(s.buff) == EncryptedChunkSize {
+			if err := s.writeChunk(false); err != nil {
+				return n, err
+			}
+		}
+
+		k := copy(s.buff[len(s.buff):EncryptedChunkSize], p[n:])
+		s.buff = s.buff[:len(s.buff)+k]
+		n += k
+	}
+
+	return n, nil
+}
+
+// Close seals the last chunk. It does nothing if the storage is used for reading
+func (s *EncryptedStorage) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.closed || s.w == nil || s.mode == encryptedModeRead {
+		return nil
+	}
+	s.closed = true
+
+	if s.mode == encryptedModeNone {
+		if err := s.writeHeader(); err != nil {
+			return err
+		}
+		s.mode = encryptedModeWrite
+	}
+
+	return s.writeChunk(true)
+}
+
+func (s *EncryptedStorage) readHeader() error {
+	header := make([]byte, EncryptedHeaderSize)
+	if _, err := io.ReadFull(s.r, header); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	return s.init(header[:EncryptedSaltSize], header[EncryptedSaltSize:])
+}
+
+func (s *EncryptedStorage) readChunk() error {
+	header := make([]byte, encryptedChunkHeaderSize)
+	if _, err := io.ReadFull(s.r, header); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(header)
+	final := length&encryptedChunkFinal != 0
+	length &^= encryptedChunkFinal
+
+	if length > EncryptedChunkSize {
+		return ErrDecrypt
+	}
+
+	chunk := make([]byte, int(length)+s.aead.Overhead())
+	if _, err := io.ReadFull(s.r, chunk); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	plaintext, err := s.aead.Open(chunk[:0], s.chunkNonce(), chunk, header)
+	if err != nil {
+		return ErrDecrypt
+	}
+
+	s.buff = plaintext
+	s.final = final
+
+	return nil
+}
+
+// Read implements io.Reader interface
+func (s *EncryptedStorage) Read(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.r == nil || s.mode == encryptedModeWrite {
+		return 0, ErrEncryptedMode
+	}
+
+	if s.mode == encryptedModeNone {
+		if err := s.readHeader(); err != nil {
+			return 0, err
+		}
+		s.mode = encryptedModeRead
+	}
+
+	for len(s.buff) == 0 {
+		if s.final {
+			return 0, io.EOF
+		}
+
+		if err := s.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n = copy(p, s.buff)
+	s.buff = s.buff[n:]
+
+	return n, nil
+}
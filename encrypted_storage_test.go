@@ -0,0 +1,104 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_EncryptedStorage_RoundTrip(t *testing.T) {
+	payloads := [][]byte{
+		{},
+		[]byte("secret payload"),
+		randomBytes(t, EncryptedChunkSize),
+		randomBytes(t, EncryptedChunkSize*2+100),
+	}
+
+	for i, payload := range payloads {
+		buff := bytes.NewBuffer(nil)
+
+		enc := NewEncryptedWriter(buff, []byte("passphrase"))
+		n, err := writeChunks(enc, payload, 1000)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, len(payload), n, "Test index %d", i)
+		require.Nil(t, enc.Close(), "Test index %d", i)
+
+		require.False(t, len(payload) > 0 && bytes.Contains(buff.Bytes(), payload), "Test index %d", i)
+
+		// Garbage after the last chunk must be ignored
+		buff.Write([]byte("garbage"))
+
+		actual, err := ioutil.ReadAll(NewEncryptedReader(buff, []byte("passphrase")))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, len(payload), len(actual), "Test index %d", i)
+		require.True(t, bytes.Equal(payload, actual), "Test index %d", i)
+	}
+}
+
+func Test_EncryptedStorage_Image(t *testing.T) {
+	rect := image.Rect(0, 0, 20, 20)
+	img := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+	payload := []byte("secret payload")
+
+	enc := NewEncryptedStorage(img, []byte("passphrase"))
+	_, err := enc.Write(payload)
+	require.Nil(t, err)
+	require.Nil(t, enc.Close())
+
+	_, err = enc.Read(make([]byte, 1))
+	require.Equal(t, ErrEncryptedMode, err)
+	_, err = enc.Write(payload)
+	require.Equal(t, ErrEncryptedClosed, err)
+
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+
+	dec := NewEncryptedStorage(img, []byte("passphrase"))
+	actual, err := ioutil.ReadAll(dec)
+	require.Nil(t, err)
+	require.Equal(t, payload, actual)
+
+	_, err = dec.Write(payload)
+	require.Equal(t, ErrEncryptedMode, err)
+}
+
+func Test_EncryptedStorage_Errors(t *testing.T) {
+	buff := bytes.NewBuffer(nil)
+	enc := NewEncryptedWriter(buff, []byte("passphrase"))
+	_, err := enc.Write(randomBytes(t, EncryptedChunkSize+10))
+	require.Nil(t, err)
+	require.Nil(t, enc.Close())
+	data := buff.Bytes()
+
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-1] ^= 0x01
+
+	// Drop the last chunk: the rest has to be reported as truncated
+	truncated := data[:EncryptedHeaderSize+encryptedChunkHeaderSize+EncryptedChunkSize+16]
+
+	tests := []struct {
+		data        []byte
+		passphrase  string
+		expectedErr error
+	}{
+		{data, "passphrase", nil},
+		{data, "wrong passphrase", ErrDecrypt},
+		{corrupted, "passphrase", ErrDecrypt},
+		{truncated, "passphrase", io.ErrUnexpectedEOF},
+		{data[:EncryptedHeaderSize-1], "passphrase", io.ErrUnexpectedEOF},
+	}
+
+	for i, test := range tests {
+		_, err := ioutil.ReadAll(NewEncryptedReader(bytes.NewReader(test.data), []byte(test.passphrase)))
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+	}
+
+	_, err = NewEncryptedReader(bytes.NewReader(data), nil).Write([]byte{1})
+	require.Equal(t, ErrEncryptedMode, err)
+	_, err = NewEncryptedWriter(buff, nil).Read(make([]byte, 1))
+	require.Equal(t, ErrEncryptedMode, err)
+}
@@ -0,0 +1,243 @@
+package imgio
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var ErrFECParity = errors.New("Invalid number of parity bytes")
+
+// FECStorage protects data of underlying storage with Reed-Solomon code. The storage is split
+// into blocks of 255 bytes, the last block may be shorter. Every block keeps parity bytes at the
+// end and corrects up to parity/2 corrupted bytes on reading. Bursts of corrupted bytes should
+// be spread between blocks, for example with RandPointsSequenceGenerator
+type FECStorage struct {
+	storage Storage
+	parity  int
+	gen     []byte
+	size    int64
+
+	// Decoded data of the last accessed block
+	block      []byte
+	blockIndex int64
+
+	position int64
+	mux      sync.Mutex
+}
+
+// NewFECStorage returns FEC storage with parity bytes per block of 255 bytes over storage. Size
+// of storage is determined by seeking to its end
+func NewFECStorage(storage Storage, parity int) (*FECStorage, error) {
+	if parity < 2 || parity >= rsCodewordSize {
+		return nil, ErrFECParity
+	}
+
+	size, err := storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := storage.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return &FECStorage{
+		storage:    storage,
+		parity:     parity,
+		gen:        rsGeneratorPoly(parity),
+		size:       size,
+		blockIndex: -1,
+	}, nil
+}
+
+// blockBounds returns offset of the block in underlying storage and size of its data
+func (s *FECStorage) blockBounds(index int64) (offset int64, dataSize int) {
+	offset = index * rsCodewordSize
+	codewordSize := s.size - offset
+	if codewordSize > rsCodewordSize {
+		codewordSize = rsCodewordSize
+	}
+	return offset, int(codewordSize) - s.parity
+}
+
+func (s *FECStorage) blockDataSize() int64 {
+	return rsCodewordSize - int64(s.parity)
+}
+
+// Size returns number of data bytes which can be stored
+func (s *FECStorage) Size() int64 {
+	full := s.size / rsCodewordSize
+	size := full * s.blockDataSize()
+	if tail := s.size - full*rsCodewordSize; tail > int64(s.parity) {
+		size += tail - int64(s.parity)
+	}
+	return size
+}
+
+func (s *FECStorage) readBlock(index int64) error {
+	if s.blockIndex == index {
+		return nil
+	}
+
+	offset, dataSize := s.blockBounds(index)
+	codeword := make([]byte, dataSize+s.parity)
+
+	if _, err := s.storage.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(s.storage, codeword); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	if err := rsDecode(codeword, s.parity); err != nil {
+		return err
+	}
+
+	s.block = codeword[:dataSize]
+	s.blockIndex = index
+
+	return nil
+}
+
+func (s *FECStorage) writeBlock(index int64, data []byte) error {
+	offset, _ := s.blockBounds(index)
+
+	if _, err := s.storage.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := s.storage.Write(append(append([]byte{}, data...), rsEncode(data, s.gen)...)); err != nil {
+		return err
+	}
+
+	s.block = data
+	s.blockIndex = index
+
+	return nil
+}
+
+func (s *FECStorage) readAt(p []byte, off int64) (n int, err error) {
+	size := s.Size()
+
+	for n < len(p) && off < size {
+		index := off / s.blockDataSize()
+		if err := s.readBlock(index); err != nil {
+			return n, err
+		}
+
+		k := copy(p[n:], s.block[off-index*s.blockDataSize():])
+		n += k
+		off += int64(k)
+	}
+
+	if off >= size {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func (s *FECStorage) writeAt(p []byte, off int64) (n int, err error) {
+	size := s.Size()
+
+	for n < len(p) && off < size {
+		index := off / s.blockDataSize()
+		_, dataSize := s.blockBounds(index)
+		start := int(off - index*s.blockDataSize())
+
+		var data []byte
+		if start == 0 && len(p)-n >= dataSize {
+			data = make([]byte, dataSize)
+		} else {
+			// Rest of partially overwritten block is kept. Block which has never been written
+			// can't be decoded, it is overwritten entirely
+			if err := s.readBlock(index); err == nil {
+				data = append([]byte{}, s.block...)
+			} else {
+				data = make([]byte, dataSize)
+			}
+		}
+
+		k := copy(data[start:], p[n:])
+		if err := s.writeBlock(index, data); err != nil {
+			return n, err
+		}
+
+		n += k
+		off += int64(k)
+	}
+
+	if n < len(p) {
+		return n, ErrOverflow
+	}
+
+	return n, nil
+}
+
+// Read implements io.Reader interface. It returns ErrReedSolomon if a block is corrupted too much
+func (s *FECStorage) Read(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n, err = s.readAt(p, s.position)
+	s.position += int64(n)
+	return
+}
+
+// Write implements io.Writer interface
+func (s *FECStorage) Write(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n, err = s.writeAt(p, s.position)
+	s.position += int64(n)
+	return
+}
+
+// ReadAt implements io.ReaderAt interface. It doesn't move the cursor of the storage
+func (s *FECStorage) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n, err = s.readAt(p, off)
+	if n == len(p) {
+		err = nil
+	}
+	return
+}
+
+// WriteAt implements io.WriterAt interface. It doesn't move the cursor of the storage
+func (s *FECStorage) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.writeAt(p, off)
+}
+
+// Seek implements io.Seeker interface
+func (s *FECStorage) Seek(offset int64, whence int) (int64, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	pos, err := seekPosition(offset, whence, s.position, s.Size())
+	if err != nil {
+		return s.position, err
+	}
+
+	s.position = pos
+
+	return pos, nil
+}
@@ -0,0 +1,146 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_NewFECStorage(t *testing.T) {
+	tests := []struct {
+		parity int
+
+		expectedErr error
+	}{
+		{0, ErrFECParity},
+		{1, ErrFECParity},
+		{2, nil},
+		{64, nil},
+		{254, nil},
+		{255, ErrFECParity},
+	}
+
+	for i, test := range tests {
+		_, err := NewFECStorage(newTestImageGroup(), test.parity)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+	}
+}
+
+func Test_FECStorage_Size(t *testing.T) {
+	tests := []struct {
+		width, height int
+		parity        int
+
+		expectedSize int64
+	}{
+		// 400 bytes: full block and block of 145 bytes
+		{10, 10, 16, 239 + 129},
+		{10, 10, 64, 191 + 81},
+		// 1020 bytes: four full blocks
+		{15, 17, 32, 4 * 223},
+		// 4 bytes: block shorter than parity is not used
+		{1, 1, 4, 0},
+	}
+
+	for i, test := range tests {
+		rect := image.Rect(0, 0, test.width, test.height)
+		img := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+
+		storage, err := NewFECStorage(img, test.parity)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedSize, storage.Size(), "Test index %d", i)
+	}
+}
+
+func Test_FECStorage_Corruption(t *testing.T) {
+	rect := image.Rect(0, 0, 10, 10)
+	img := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+
+	storage, err := NewFECStorage(img, 16)
+	require.Nil(t, err)
+
+	data := randomBytes(t, storage.Size())
+	_, err = storage.Write(data)
+	require.Nil(t, err)
+
+	corrupt := func(off int64) {
+		b := make([]byte, 1)
+		_, err := img.ReadAt(b, off)
+		require.Nil(t, err)
+		b[0] ^= 0x5a
+		_, err = img.WriteAt(b, off)
+		require.Nil(t, err)
+	}
+
+	// 8 corrupted bytes in every block are corrected
+	for _, off := range []int64{0, 10, 50, 100, 150, 200, 238, 254, 255, 300, 350, 360, 370, 380, 390, 399} {
+		corrupt(off)
+	}
+
+	actual, err := ioutil.ReadAll(io.NewSectionReader(img, 0, 400))
+	require.Nil(t, err)
+	require.False(t, bytes.Equal(data[:239], actual[:239]))
+
+	storage, err = NewFECStorage(img, 16)
+	require.Nil(t, err)
+	actual, err = ioutil.ReadAll(storage)
+	require.Nil(t, err)
+	require.Equal(t, data, actual)
+
+	// The 9th corrupted byte of the first block can't be corrected
+	corrupt(20)
+
+	storage, err = NewFECStorage(img, 16)
+	require.Nil(t, err)
+	_, err = ioutil.ReadAll(storage)
+	require.Equal(t, ErrReedSolomon, err)
+}
+
+func Test_FECStorage_JPEG(t *testing.T) {
+	rect := image.Rect(0, 0, 96, 96)
+	key := []byte("secret")
+
+	tests := []struct {
+		quality int
+		parity  int
+	}{
+		{30, 96},
+		{40, 32},
+		{60, 16},
+		{90, 8},
+	}
+
+	for i, test := range tests {
+		img := image.NewYCbCr(rect, image.YCbCrSubsampleRatio420)
+		storage, err := NewFECStorage(
+			NewImageReadWriterYCbCr(img, NewRandPointsSequenceGenerator(rect, key), PointReadWriterYCbCrLuma{}),
+			test.parity,
+		)
+		require.Nil(t, err, "Test index %d", i)
+
+		data := randomBytes(t, storage.Size())
+		_, err = storage.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		buff := bytes.NewBuffer(nil)
+		require.Nil(t, jpeg.Encode(buff, img, &jpeg.Options{Quality: test.quality}), "Test index %d", i)
+
+		decoded, err := jpeg.Decode(buff)
+		require.Nil(t, err, "Test index %d", i)
+
+		storage, err = NewFECStorage(
+			NewImageReadWriterYCbCr(decoded.(*image.YCbCr), NewRandPointsSequenceGenerator(rect, key), PointReadWriterYCbCrLuma{}),
+			test.parity,
+		)
+		require.Nil(t, err, "Test index %d", i)
+
+		actual, err := ioutil.ReadAll(storage)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+	}
+}
@@ -0,0 +1,228 @@
+package imgio
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	FrameMagic   = "IMIO"
+	FrameVersion = 1
+
+	// FrameHeaderSize is size of encoded frame header in bytes: magic, version, codec, codec
+	// parameters, flags, payload length and CRC32 checksum of the payload
+	FrameHeaderSize = len(FrameMagic) + 1 + 1 + 2 + 1 + 8 + 4
+)
+
+// Flags of frame header describe how the payload is transformed before embedding
+const (
+	FrameFlagEncrypted uint8 = 1 << iota
+	FrameFlagCompressed
+	// FrameFlagPart marks frame which keeps one part of payload split across several images.
+	// Payload of the frame starts with PartManifest
+	FrameFlagPart
+)
+
+var (
+	ErrFrameMagic   = errors.New("Invalid frame magic")
+	ErrFrameVersion = errors.New("Unsupported frame version")
+	ErrFrameClosed  = errors.New("Frame is closed")
+	ErrChecksum     = errors.New("Checksum mismatch")
+)
+
+type FrameHeader struct {
+	Version     uint8
+	Codec       CodecID
+	CodecParams uint16
+	Flags       uint8
+	Length      uint64
+	Checksum    uint32
+}
+
+func (h FrameHeader) MarshalBinary() ([]byte, error) {
+	buff := make([]byte, FrameHeaderSize)
+	n := copy(buff, FrameMagic)
+	buff[n] = h.Version
+	buff[n+1] = byte(h.Codec)
+	binary.BigEndian.PutUint16(buff[n+2:], h.CodecParams)
+	buff[n+4] = h.Flags
+	binary.BigEndian.PutUint64(buff[n+5:], h.Length)
+	binary.BigEndian.PutUint32(buff[n+13:], h.Checksum)
+	return buff, nil
+}
+
+func (h *FrameHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < FrameHeaderSize || string(data[:len(FrameMagic)]) != FrameMagic {
+		return ErrFrameMagic
+	}
+
+	n := len(FrameMagic)
+	if data[n] != FrameVersion {
+		return ErrFrameVersion
+	}
+
+	h.Version = data[n]
+	h.Codec = CodecID(data[n+1])
+	h.CodecParams = binary.BigEndian.Uint16(data[n+2:])
+	h.Flags = data[n+4]
+	h.Length = binary.BigEndian.Uint64(data[n+5:])
+	h.Checksum = binary.BigEndian.Uint32(data[n+13:])
+	return nil
+}
+
+// ReadFrameHeader reads and decodes frame header from r
+func ReadFrameHeader(r io.Reader) (FrameHeader, error) {
+	var header FrameHeader
+
+	buff := make([]byte, FrameHeaderSize)
+	if _, err := io.ReadFull(r, buff); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return header, ErrFrameMagic
+		}
+		return header, err
+	}
+
+	err := header.UnmarshalBinary(buff)
+	return header, err
+}
+
+// FrameWriter writes payload into a storage prefixed with frame header. Payload length and
+// checksum are unknown until the payload is written entirely, so the header is rewritten on Close
+type FrameWriter struct {
+	storage io.WriteSeeker
+	header  FrameHeader
+	start   int64
+	crc     hash.Hash32
+	closed  bool
+}
+
+// NewFrameWriter writes placeholder of the frame header on the current position of storage
+func NewFrameWriter(storage io.WriteSeeker, codec CodecID, codecParams uint16, flags uint8) (*FrameWriter, error) {
+	start, err := storage.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &FrameWriter{
+		storage: storage,
+		header: FrameHeader{
+			Version:     FrameVersion,
+			Codec:       codec,
+			CodecParams: codecParams,
+			Flags:       flags,
+		},
+		start: start,
+		crc:   crc32.NewIEEE(),
+	}
+
+	if err := fw.writeHeader(); err != nil {
+		return nil, err
+	}
+
+	return fw, nil
+}
+
+func (fw *FrameWriter) writeHeader() error {
+	buff, err := fw.header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = fw.storage.Write(buff)
+	return err
+}
+
+// Write implements io.Writer interface
+func (fw *FrameWriter) Write(p []byte) (n int, err error) {
+	if fw.closed {
+		return 0, ErrFrameClosed
+	}
+
+	n, err = fw.storage.Write(p)
+	fw.crc.Write(p[:n])
+	fw.header.Length += uint64(n)
+	return
+}
+
+// Close writes the final frame header and moves cursor of the storage to the end of the payload
+func (fw *FrameWriter) Close() error {
+	if fw.closed {
+		return nil
+	}
+	fw.closed = true
+
+	fw.header.Checksum = fw.crc.Sum32()
+
+	if _, err := fw.storage.Seek(fw.start, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := fw.writeHeader(); err != nil {
+		return err
+	}
+
+	_, err := fw.storage.Seek(int64(fw.header.Length), io.SeekCurrent)
+	return err
+}
+
+// Header returns frame header. Length and checksum are final only after Close
+func (fw *FrameWriter) Header() FrameHeader {
+	return fw.header
+}
+
+// FrameReader reads exactly the payload of a frame and verifies its checksum
+type FrameReader struct {
+	storage io.Reader
+	header  FrameHeader
+	remain  uint64
+	crc     hash.Hash32
+}
+
+// NewFrameReader reads frame header from the current position of storage
+func NewFrameReader(storage io.Reader) (*FrameReader, error) {
+	header, err := ReadFrameHeader(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FrameReader{
+		storage: storage,
+		header:  header,
+		remain:  header.Length,
+		crc:     crc32.NewIEEE(),
+	}, nil
+}
+
+// Read implements io.Reader interface. It returns ErrChecksum instead of io.EOF if the payload is
+// corrupted
+func (fr *FrameReader) Read(p []byte) (n int, err error) {
+	if fr.remain == 0 {
+		if fr.crc.Sum32() != fr.header.Checksum {
+			return 0, ErrChecksum
+		}
+		return 0, io.EOF
+	}
+
+	if uint64(len(p)) > fr.remain {
+		p = p[:fr.remain]
+	}
+
+	n, err = fr.storage.Read(p)
+	fr.crc.Write(p[:n])
+	fr.remain -= uint64(n)
+
+	if err == io.EOF {
+		if fr.remain > 0 {
+			return n, io.ErrUnexpectedEOF
+		}
+		err = nil
+	}
+
+	return
+}
+
+func (fr *FrameReader) Header() FrameHeader {
+	return fr.header
+}
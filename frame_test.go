@@ -0,0 +1,148 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_FrameHeader_MarshalBinary(t *testing.T) {
+	header := FrameHeader{
+		Version:     FrameVersion,
+		Codec:       CodecChannelBits,
+		CodecParams: 0x0307,
+		Flags:       0x01,
+		Length:      0x0102030405060708,
+		Checksum:    0xdeadbeef,
+	}
+
+	data, err := header.MarshalBinary()
+	require.Nil(t, err)
+	require.Len(t, data, FrameHeaderSize)
+	require.Equal(t, []byte{
+		'I', 'M', 'I', 'O', 0x01, 0x05, 0x03, 0x07, 0x01,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0xde, 0xad, 0xbe, 0xef,
+	}, data)
+
+	var decoded FrameHeader
+	require.Nil(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, header, decoded)
+}
+
+func Test_FrameHeader_UnmarshalBinary_Errors(t *testing.T) {
+	valid, _ := FrameHeader{Version: FrameVersion}.MarshalBinary()
+
+	badVersion := append([]byte{}, valid...)
+	badVersion[len(FrameMagic)] = FrameVersion + 1
+
+	tests := []struct {
+		data        []byte
+		expectedErr error
+	}{
+		{valid, nil},
+		{valid[:FrameHeaderSize-1], ErrFrameMagic},
+		{append([]byte("IMGO"), valid[len(FrameMagic):]...), ErrFrameMagic},
+		{badVersion, ErrFrameVersion},
+	}
+
+	for i, test := range tests {
+		var header FrameHeader
+		require.Equal(t, test.expectedErr, header.UnmarshalBinary(test.data), "Test index %d", i)
+	}
+}
+
+func Test_Frame_RoundTrip(t *testing.T) {
+	payloads := [][]byte{
+		{},
+		{1},
+		[]byte("payload of the frame"),
+		randomBytes(t, 300),
+	}
+
+	for name, newStorage := range testStorages() {
+		for i, payload := range payloads {
+			storage := newStorage()
+			if int64(len(payload)+FrameHeaderSize) > storage.(sizer).Size() {
+				continue
+			}
+
+			fw, err := NewFrameWriter(storage, CodecSimplePoint32, 0, 0)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+			n, err := fw.Write(payload)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+			require.Equal(t, len(payload), n, "Storage %s, test index %d", name, i)
+			require.Nil(t, fw.Close(), "Storage %s, test index %d", name, i)
+
+			pos, err := storage.Seek(0, io.SeekCurrent)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+			require.Equal(t, int64(FrameHeaderSize+len(payload)), pos, "Storage %s, test index %d", name, i)
+
+			_, err = storage.Seek(0, io.SeekStart)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+
+			fr, err := NewFrameReader(storage)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+			require.Equal(t, uint64(len(payload)), fr.Header().Length, "Storage %s, test index %d", name, i)
+			require.Equal(t, CodecSimplePoint32, fr.Header().Codec, "Storage %s, test index %d", name, i)
+
+			actual, err := ioutil.ReadAll(fr)
+			require.Nil(t, err, "Storage %s, test index %d", name, i)
+			require.Equal(t, payload, actual, "Storage %s, test index %d", name, i)
+		}
+	}
+}
+
+func Test_Frame_Checksum(t *testing.T) {
+	rect := image.Rect(0, 0, 10, 10)
+	img := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+
+	fw, err := NewFrameWriter(img, CodecSimplePoint32, 0, 0)
+	require.Nil(t, err)
+	_, err = fw.Write([]byte("payload of the frame"))
+	require.Nil(t, err)
+	require.Nil(t, fw.Close())
+
+	_, err = img.WriteAt([]byte{'P'}, int64(FrameHeaderSize))
+	require.Nil(t, err)
+
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+
+	fr, err := NewFrameReader(img)
+	require.Nil(t, err)
+
+	actual, err := ioutil.ReadAll(fr)
+	require.Equal(t, ErrChecksum, err)
+	require.Equal(t, []byte("Payload of the frame"), actual)
+}
+
+func Test_FrameReader_Errors(t *testing.T) {
+	header, _ := FrameHeader{Version: FrameVersion, Length: 10}.MarshalBinary()
+
+	_, err := NewFrameReader(bytes.NewReader(make([]byte, FrameHeaderSize*2)))
+	require.Equal(t, ErrFrameMagic, err)
+
+	_, err = NewFrameReader(bytes.NewReader(header[:5]))
+	require.Equal(t, ErrFrameMagic, err)
+
+	fr, err := NewFrameReader(bytes.NewReader(append(header, 1, 2, 3)))
+	require.Nil(t, err)
+	_, err = ioutil.ReadAll(fr)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func Test_FrameWriter_Closed(t *testing.T) {
+	fw, err := NewFrameWriter(newTestImageGroup(), CodecUnknown, 0, FrameFlagEncrypted)
+	require.Nil(t, err)
+	require.Nil(t, fw.Close())
+	require.Equal(t, FrameFlagEncrypted, fw.Header().Flags)
+
+	n, err := fw.Write([]byte{1})
+	require.Equal(t, ErrFrameClosed, err)
+	require.Zero(t, n)
+}
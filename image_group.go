@@ -2,15 +2,27 @@ package imgio
 
 import (
 	"io"
+	"sync"
 )
 
+// GroupStorage is storage which can be a member of ImageGroup
+type GroupStorage interface {
+	Storage
+	io.ReaderAt
+	io.WriterAt
+	// Size returns number of bytes which can be stored in the storage
+	Size() int64
+}
+
 type ImageGroup struct {
-	images []*rwImage
-	cursor int
+	images   []GroupStorage
+	cursor   int
+	position int64
+	mux      sync.Mutex
 }
 
-func NewImageGroup(images ...*rwImage) *ImageGroup {
-	i := make([]*rwImage, len(images))
+func NewImageGroup(images ...GroupStorage) *ImageGroup {
+	i := make([]GroupStorage, len(images))
 	copy(i, images)
 	return &ImageGroup{
 		images: i,
@@ -20,19 +32,29 @@ func NewImageGroup(images ...*rwImage) *ImageGroup {
 
 // Read implements io.Reader interface
 func (ig *ImageGroup) Read(p []byte) (n int, err error) {
-	if ig.cursor >= len(ig.images) {
-		return 0, io.EOF
+	ig.mux.Lock()
+	defer ig.mux.Unlock()
+	return ig.read(p)
+}
+
+func (ig *ImageGroup) read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
 	}
 
-	n, err = ig.images[ig.cursor].Read(p)
+	for ig.cursor < len(ig.images) {
+		n, err = ig.images[ig.cursor].Read(p)
+		ig.position += int64(n)
 
-	if n > 0 || err != io.EOF {
-		if err == io.EOF && len(ig.images) > ig.cursor {
-			// Don't return io.EOF yet. More images remain.
+		if err == io.EOF {
+			// Don't return io.EOF yet. More images may remain.
 			err = nil
 			ig.cursor++
 		}
-		return
+
+		if n > 0 || err != nil {
+			return
+		}
 	}
 
 	return 0, io.EOF
@@ -40,10 +62,17 @@ func (ig *ImageGroup) Read(p []byte) (n int, err error) {
 
 // Write implements io.Writer interface
 func (ig *ImageGroup) Write(p []byte) (n int, err error) {
+	ig.mux.Lock()
+	defer ig.mux.Unlock()
+	return ig.write(p)
+}
+
+func (ig *ImageGroup) write(p []byte) (n int, err error) {
 	var written int
 
 	for ig.cursor < len(ig.images) && len(p) > 0 {
 		written, err = ig.images[ig.cursor].Write(p)
+		ig.position += int64(written)
 		n += written
 		p = p[written:]
 
@@ -64,6 +93,120 @@ func (ig *ImageGroup) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// Seek implements io.Seeker interface
+func (ig *ImageGroup) Seek(offset int64, whence int) (int64, error) {
+	ig.mux.Lock()
+	defer ig.mux.Unlock()
+
+	pos, err := seekPosition(offset, whence, ig.position, ig.Size())
+	if err != nil {
+		return ig.position, err
+	}
+
+	if err := ig.seek(pos); err != nil {
+		return ig.position, err
+	}
+
+	return pos, nil
+}
+
+// seek sets cursor to the image which contains byte on position pos. Images before the cursor
+// are moved to the end and images after the cursor are rewound
+func (ig *ImageGroup) seek(pos int64) error {
+	ig.cursor = len(ig.images)
+	ig.position = pos
+
+	for i, image := range ig.images {
+		var offset int64
+
+		if ig.cursor == len(ig.images) {
+			if size := image.Size(); pos < size {
+				ig.cursor = i
+				offset = pos
+			} else {
+				pos -= size
+				offset = size
+			}
+		}
+
+		if _, err := image.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadAt implements io.ReaderAt interface. It doesn't move the cursor of the group
+func (ig *ImageGroup) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	for _, image := range ig.images {
+		if len(p) == 0 {
+			return n, nil
+		}
+
+		size := image.Size()
+		if off >= size {
+			off -= size
+			continue
+		}
+
+		var read int
+		read, err = image.ReadAt(p, off)
+		n += read
+		p = p[read:]
+		off = 0
+
+		if err != nil && err != io.EOF {
+			return
+		}
+	}
+
+	if len(p) > 0 {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// WriteAt implements io.WriterAt interface. It doesn't move the cursor of the group
+func (ig *ImageGroup) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	for _, image := range ig.images {
+		if len(p) == 0 {
+			return n, nil
+		}
+
+		size := image.Size()
+		if off >= size {
+			off -= size
+			continue
+		}
+
+		var written int
+		written, err = image.WriteAt(p, off)
+		n += written
+		p = p[written:]
+		off = 0
+
+		if err != nil && err != ErrOverflow {
+			return
+		}
+	}
+
+	if len(p) > 0 {
+		return n, ErrOverflow
+	}
+
+	return n, nil
+}
+
 func (ig *ImageGroup) Size() (size int64) {
 	for _, image := range ig.images {
 		size += image.Size()
@@ -72,8 +215,7 @@ func (ig *ImageGroup) Size() (size int64) {
 }
 
 func (ig *ImageGroup) Rewind() {
-	ig.cursor = 0
-	for _, image := range ig.images {
-		image.gen.Rewind()
-	}
+	ig.mux.Lock()
+	defer ig.mux.Unlock()
+	ig.seek(0)
 }
@@ -13,15 +13,15 @@ import (
 
 func Test_ImageGroup_ReadWriteHash_OneImage(t *testing.T) {
 	group := &ImageGroup{
-		images: []*rwImage{
-			{
-				img: image.NewRGBA(image.Rect(0, 0, 100, 100)),
-				gen: &SimplePointsSequenceGenerator{
+		images: []GroupStorage{
+			NewImage(
+				image.NewRGBA(image.Rect(0, 0, 100, 100)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 100),
 					cursor: 0,
 				},
-				prw: GentlePoint16ReadWriter{},
-			},
+				GentlePoint16ReadWriter{},
+			),
 		},
 	}
 	hasher := md5.New()
@@ -44,47 +44,47 @@ func Test_ImageGroup_ReadWriteHash_OneImage(t *testing.T) {
 
 func Test_ImageGroup_ReadWriteHash_ManyImage(t *testing.T) {
 	group := &ImageGroup{
-		images: []*rwImage{
-			{
-				img: image.NewRGBA(image.Rect(0, 0, 100, 100)),
-				gen: &SimplePointsSequenceGenerator{
+		images: []GroupStorage{
+			NewImage(
+				image.NewRGBA(image.Rect(0, 0, 100, 100)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 100),
 					cursor: 0,
 				},
-				prw: GentlePoint16ReadWriter{},
-			},
-			{
-				img: image.NewRGBA(image.Rect(0, 0, 100, 100)),
-				gen: &SimplePointsSequenceGenerator{
+				GentlePoint16ReadWriter{},
+			),
+			NewImage(
+				image.NewRGBA(image.Rect(0, 0, 100, 100)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 100),
 					cursor: 0,
 				},
-				prw: SimplePoint32ReadWriter{},
-			},
-			{
-				img: image.NewRGBA64(image.Rect(0, 0, 100, 100)),
-				gen: &SimplePointsSequenceGenerator{
+				SimplePoint32ReadWriter{},
+			),
+			NewImage(
+				image.NewRGBA64(image.Rect(0, 0, 100, 100)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 100),
 					cursor: 0,
 				},
-				prw: SimplePoint64ReadWriter{},
-			},
-			{
-				img: image.NewRGBA(image.Rect(0, 0, 100, 10)),
-				gen: &SimplePointsSequenceGenerator{
+				SimplePoint64ReadWriter{},
+			),
+			NewImage(
+				image.NewRGBA(image.Rect(0, 0, 100, 10)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 10),
 					cursor: 0,
 				},
-				prw: SimplePoint32ReadWriter{},
-			},
-			{
-				img: image.NewRGBA64(image.Rect(0, 0, 100, 52)),
-				gen: &SimplePointsSequenceGenerator{
+				SimplePoint32ReadWriter{},
+			),
+			NewImage(
+				image.NewRGBA64(image.Rect(0, 0, 100, 52)),
+				&SimplePointsSequenceGenerator{
 					rect:   image.Rect(0, 0, 100, 52),
 					cursor: 0,
 				},
-				prw: SimplePoint64ReadWriter{},
-			},
+				SimplePoint64ReadWriter{},
+			),
 		},
 	}
 	hasher := md5.New()
@@ -104,3 +104,141 @@ func Test_ImageGroup_ReadWriteHash_ManyImage(t *testing.T) {
 	secondSum := hasher.Sum(nil)
 	require.Equal(t, firstSum, secondSum)
 }
+
+func newTestImageGroup() *ImageGroup {
+	return NewImageGroup(
+		NewImage(
+			image.NewRGBA(image.Rect(0, 0, 2, 1)),
+			NewSimplePointsSequenceGenerator(image.Rect(0, 0, 2, 1)),
+			SimplePoint32ReadWriter{},
+		),
+		NewImage(
+			image.NewRGBA(image.Rect(0, 0, 1, 1)),
+			NewSimplePointsSequenceGenerator(image.Rect(0, 0, 1, 1)),
+			GentlePoint16ReadWriter{},
+		),
+		NewImage(
+			image.NewRGBA64(image.Rect(0, 0, 2, 1)),
+			NewSimplePointsSequenceGenerator(image.Rect(0, 0, 2, 1)),
+			SimplePoint64ReadWriter{},
+		),
+	)
+}
+
+func Test_ImageGroup_ImplementsInterfaces(t *testing.T) {
+	group := newTestImageGroup()
+	require.Implements(t, (*Storage)(nil), group)
+	require.Implements(t, (*io.ReaderAt)(nil), group)
+	require.Implements(t, (*io.WriterAt)(nil), group)
+}
+
+func Test_ImageGroup_Seek(t *testing.T) {
+	group := newTestImageGroup()
+	require.EqualValues(t, 26, group.Size())
+
+	data := []byte("abcdefghijklmnopqrstuvwxyz")
+	n, err := group.Write(data)
+	require.Nil(t, err)
+	require.Equal(t, len(data), n)
+
+	tests := []struct {
+		offset int64
+		whence int
+		size   int
+
+		expectedPos  int64
+		expectedData []byte
+	}{
+		{0, io.SeekStart, 4, 0, []byte("abcd")},
+		{8, io.SeekStart, 2, 8, []byte("ij")},
+		{-2, io.SeekCurrent, 2, 8, []byte("ij")},
+		{-8, io.SeekEnd, 8, 18, []byte("stuvwxyz")},
+		{10, io.SeekStart, 8, 10, []byte("klmnopqr")},
+	}
+
+	for i, test := range tests {
+		pos, err := group.Seek(test.offset, test.whence)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedPos, pos, "Test index %d", i)
+
+		buff := make([]byte, test.size)
+		_, err = io.ReadFull(group, buff)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedData, buff, "Test index %d", i)
+	}
+
+	pos, err := group.Seek(0, io.SeekEnd)
+	require.Nil(t, err)
+	require.EqualValues(t, 26, pos)
+	n, err = group.Read(make([]byte, 1))
+	require.Equal(t, 0, n)
+	require.Equal(t, io.EOF, err)
+
+	pos, err = group.Seek(1, io.SeekCurrent)
+	require.Equal(t, ErrSeekOutOfRange, err)
+	require.EqualValues(t, 26, pos)
+}
+
+func Test_ImageGroup_ReadAt(t *testing.T) {
+	group := newTestImageGroup()
+
+	data := []byte("abcdefghijklmnopqrstuvwxyz")
+	n, err := group.Write(data)
+	require.Nil(t, err)
+	require.Equal(t, len(data), n)
+
+	pos, err := group.Seek(4, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 4, pos)
+
+	buff := make([]byte, 8)
+	n, err = group.ReadAt(buff, 6)
+	require.Nil(t, err)
+	require.Equal(t, 8, n)
+	require.Equal(t, []byte("ghijklmn"), buff)
+
+	n, err = group.ReadAt(buff, 22)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, []byte("wxyz"), buff[:n])
+
+	n, err = group.ReadAt(buff, 26)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 0, n)
+
+	n, err = group.ReadAt(buff, -1)
+	require.Equal(t, ErrSeekNegative, err)
+	require.Equal(t, 0, n)
+
+	// Stream position is not affected by ReadAt
+	buff = make([]byte, 4)
+	_, err = io.ReadFull(group, buff)
+	require.Nil(t, err)
+	require.Equal(t, []byte("efgh"), buff)
+}
+
+func Test_ImageGroup_WriteAt(t *testing.T) {
+	group := newTestImageGroup()
+
+	n, err := group.WriteAt([]byte("0123456789"), 6)
+	require.Nil(t, err)
+	require.Equal(t, 10, n)
+
+	n, err = group.WriteAt([]byte("xyz"), 24)
+	require.Equal(t, ErrOverflow, err)
+	require.Equal(t, 2, n)
+
+	n, err = group.WriteAt([]byte("x"), 27)
+	require.Equal(t, ErrOverflow, err)
+	require.Equal(t, 0, n)
+
+	buff := make([]byte, group.Size())
+	_, err = io.ReadFull(group, buff)
+	require.Nil(t, err)
+	require.Equal(t, []byte{
+		0, 0, 0, 0, 0, 0, '0', '1',
+		'2', '3',
+		'4', '5', '6', '7', '8', '9', 0, 0,
+		0, 0, 0, 0, 0, 0, 'x', 'y',
+	}, buff)
+}
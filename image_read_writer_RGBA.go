@@ -0,0 +1,36 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+)
+
+// ImageReadWriterRGBA is storage over points of image.RGBA
+type ImageReadWriterRGBA struct {
+	img *image.RGBA
+	prw PointReadWriterRGBA
+	*PointStorage
+}
+
+func NewImageReadWriterRGBA(img *image.RGBA, gen PointsSequenceGenerator, prw PointReadWriterRGBA) *ImageReadWriterRGBA {
+	return &ImageReadWriterRGBA{
+		img:          img,
+		prw:          prw,
+		PointStorage: NewPointStorage(NewRGBAAccessor(img, prw), gen),
+	}
+}
+
+// ColorModel implements image.Image interface
+func (i *ImageReadWriterRGBA) ColorModel() color.Model {
+	return i.img.ColorModel()
+}
+
+// Bounds implements image.Image interface
+func (i *ImageReadWriterRGBA) Bounds() image.Rectangle {
+	return i.img.Bounds()
+}
+
+// At  implements image.Image interface
+func (i *ImageReadWriterRGBA) At(x, y int) color.Color {
+	return i.img.At(x, y)
+}
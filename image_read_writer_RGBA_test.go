@@ -0,0 +1,152 @@
+package imgio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_ImageReadWriterRGBA_Write_WriteUsingPointReadWriterRGBASimple(t *testing.T) {
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(0, 0, 5, 2)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 5, 2),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+
+	n, err := imgrw.Write([]byte("testing"))
+	require.Equal(t, 7, n)
+	require.Nil(t, err)
+
+	require.Equal(t, color.RGBA{'t', 'e', 's', 't'}, imgrw.img.RGBAAt(0, 0))
+	require.Equal(t, color.RGBA{'i', 'n', 'g', 0}, imgrw.img.RGBAAt(1, 0))
+	require.Equal(t, color.RGBA{}, imgrw.img.RGBAAt(2, 0))
+}
+
+func Test_ImageReadWriterRGBA_Write_WriteUsingPointReadWriterRGBASimple_ManyPoints_ExpectsOverflow(t *testing.T) {
+	width := 10
+	height := 5
+	size := width * height * PointReadWriterRGBASimpleCapacity
+	buffSize := size * 2
+
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(0, 0, width, height)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, width, height),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+
+	buff := make([]byte, buffSize)
+	n, err := rand.Reader.Read(buff)
+	require.EqualValues(t, buffSize, n)
+	require.Nil(t, err)
+
+	n, err = imgrw.Write(buff)
+	require.EqualValues(t, size, n)
+	require.Equal(t, ErrOverflow, err)
+}
+
+func Test_ImageReadWriterRGBA_Read_ReadUsingPointReadWriterRGBASimple_ExpectsEOF(t *testing.T) {
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(0, 0, 2, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 2, 1),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+
+	imgrw.img.SetRGBA(0, 0, color.RGBA{'t', 'e', 's', 't'})
+	imgrw.img.SetRGBA(1, 0, color.RGBA{'i', 'n', 'g', 0})
+
+	size := 8
+	buff := make([]byte, size)
+	n, err := imgrw.Read(buff)
+	require.Equal(t, io.EOF, err)
+	require.EqualValues(t, size, n)
+	require.Equal(t, []byte{'t', 'e', 's', 't', 'i', 'n', 'g', 0}, buff)
+}
+
+func Test_ImageReadWriterRGBA_ReadWrite_Hash_NoSquare(t *testing.T) {
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(0, 0, 100, 11)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 100, 11),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+	hasher := md5.New()
+	buff := bytes.NewBuffer(nil)
+	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, imgrw.Size()), hasher))
+	require.Nil(t, err)
+	require.Equal(t, imgrw.Size(), n)
+	firstSum := hasher.Sum(nil)
+	hasher.Reset()
+	n, err = buff.WriteTo(imgrw)
+	require.Nil(t, err)
+	require.Equal(t, imgrw.Size(), n)
+	pos, err := imgrw.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 0, pos)
+	n, err = io.Copy(hasher, imgrw)
+	require.Nil(t, err)
+	require.Equal(t, imgrw.Size(), n)
+	secondSum := hasher.Sum(nil)
+	require.Equal(t, firstSum, secondSum)
+}
+
+func Test_ImageReadWriterRGBA_ReadAt_WriteAt(t *testing.T) {
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+
+	n, err := imgrw.WriteAt([]byte("abcdef"), 2)
+	require.Nil(t, err)
+	require.Equal(t, 6, n)
+
+	buff := make([]byte, 6)
+	n, err = imgrw.ReadAt(buff, 7)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, []byte{'f', 0, 0, 0, 0}, buff[:n])
+
+	require.Equal(t, color.RGBA{0, 0, 'a', 'b'}, imgrw.img.RGBAAt(0, 0))
+	require.Equal(t, color.RGBA{'c', 'd', 'e', 'f'}, imgrw.img.RGBAAt(1, 0))
+}
+
+func WriteBytesToImageRGBA(x0, y0, x1, y1 int) (int64, error) {
+	imgrw := NewImageReadWriterRGBA(
+		image.NewRGBA(image.Rect(x0, y0, x1, y1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(x0, y0, x1, y1),
+			cursor: 0,
+		},
+		PointReadWriterRGBASimple{},
+	)
+	return io.CopyN(imgrw, rand.Reader, imgrw.Size())
+}
+
+func Benchmark_WriteBytesToImageRGBA(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		_, err := WriteBytesToImageRGBA(0, 0, 1000, 1000)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package imgio
 import (
 	"crypto/rand"
 	"image"
+	"image/color"
 	"io"
 	"testing"
 
@@ -10,14 +11,14 @@ import (
 )
 
 func Test_ImageReadWriterYCbCr_Write_WriteUsingPointReadWriterYCbCrSimple(t *testing.T) {
-	imgrw := &ImageReadWriterYCbCr{
-		img: image.NewYCbCr(image.Rect(0, 0, 5, 2), image.YCbCrSubsampleRatio444),
-		gen: &SimplePointsSequenceGenerator{
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 5, 2), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 2),
 			cursor: 0,
 		},
-		prw: PointReadWriterYCbCrSimple{},
-	}
+		PointReadWriterYCbCrSimple{},
+	)
 
 	n, err := imgrw.Write([]byte("testing"))
 	require.Equal(t, 7, n)
@@ -39,14 +40,14 @@ func Test_ImageReadWriterYCbCr_Write_WriteUsingPointReadWriterYCbCrSimple(t *tes
 }
 
 func Test_ImageReadWriterYCbCr_Write_WriteUsingPointReadWriterYCbCrSimple_OnePoint_ExpectsOverflow(t *testing.T) {
-	imgrw := &ImageReadWriterYCbCr{
-		img: image.NewYCbCr(image.Rect(0, 0, 1, 1), image.YCbCrSubsampleRatio444),
-		gen: &SimplePointsSequenceGenerator{
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 1, 1), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 1, 1),
 			cursor: 0,
 		},
-		prw: PointReadWriterYCbCrSimple{},
-	}
+		PointReadWriterYCbCrSimple{},
+	)
 
 	n, err := imgrw.Write([]byte("testing"))
 	require.Equal(t, PointReadWriterYCbCrSimpleCapacity, n)
@@ -59,14 +60,14 @@ func Test_ImageReadWriterYCbCr_Write_WriteUsingPointReadWriterYCbCrSimple_ManyPo
 	size := width * height * PointReadWriterYCbCrSimpleCapacity
 	buffSize := size * 2
 
-	imgrw := &ImageReadWriterYCbCr{
-		img: image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio444),
-		gen: &SimplePointsSequenceGenerator{
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, width, height),
 			cursor: 0,
 		},
-		prw: PointReadWriterYCbCrSimple{},
-	}
+		PointReadWriterYCbCrSimple{},
+	)
 
 	buff := make([]byte, buffSize)
 	n, err := rand.Reader.Read(buff)
@@ -79,14 +80,14 @@ func Test_ImageReadWriterYCbCr_Write_WriteUsingPointReadWriterYCbCrSimple_ManyPo
 }
 
 func Test_ImageReadWriterYCbCr_Read_ReadUsingPointReadWriterYCbCrSimple(t *testing.T) {
-	imgrw := &ImageReadWriterYCbCr{
-		img: image.NewYCbCr(image.Rect(0, 0, 5, 4), image.YCbCrSubsampleRatio444),
-		gen: &SimplePointsSequenceGenerator{
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 5, 4), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 4),
 			cursor: 0,
 		},
-		prw: PointReadWriterYCbCrSimple{},
-	}
+		PointReadWriterYCbCrSimple{},
+	)
 
 	imgrw.img.Cb[imgrw.img.COffset(0, 0)] = 't'
 	imgrw.img.Cr[imgrw.img.COffset(0, 0)] = 'e'
@@ -104,14 +105,14 @@ func Test_ImageReadWriterYCbCr_Read_ReadUsingPointReadWriterYCbCrSimple(t *testi
 }
 
 func Test_ImageReadWriterYCbCr_Read_ReadUsingPointReadWriterYCbCrSimple_ExpectsEOF(t *testing.T) {
-	imgrw := &ImageReadWriterYCbCr{
-		img: image.NewYCbCr(image.Rect(0, 0, 2, 1), image.YCbCrSubsampleRatio444),
-		gen: &SimplePointsSequenceGenerator{
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 2, 1), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 2, 1),
 			cursor: 0,
 		},
-		prw: PointReadWriterYCbCrSimple{},
-	}
+		PointReadWriterYCbCrSimple{},
+	)
 
 	imgrw.img.Cb[imgrw.img.COffset(0, 0)] = 't'
 	imgrw.img.Cr[imgrw.img.COffset(0, 0)] = 'e'
@@ -125,3 +126,170 @@ func Test_ImageReadWriterYCbCr_Read_ReadUsingPointReadWriterYCbCrSimple_ExpectsE
 	require.EqualValues(t, size, n)
 	require.Equal(t, []byte{'t', 'e', 't', 'i'}, buff)
 }
+
+func Test_ImageReadWriterYCbCr_Seek(t *testing.T) {
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 3, 1), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		PointReadWriterYCbCrSimple{},
+	)
+
+	imgrw.img.Cb[imgrw.img.COffset(0, 0)] = 'a'
+	imgrw.img.Cr[imgrw.img.COffset(0, 0)] = 'b'
+	imgrw.img.Cb[imgrw.img.COffset(1, 0)] = 'c'
+	imgrw.img.Cr[imgrw.img.COffset(1, 0)] = 'd'
+	imgrw.img.Cb[imgrw.img.COffset(2, 0)] = 'e'
+	imgrw.img.Cr[imgrw.img.COffset(2, 0)] = 'f'
+
+	pos, err := imgrw.Seek(3, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 3, pos)
+	require.Equal(t, image.Point{1, 0}, imgrw.gen.Current())
+	require.Equal(t, 8, imgrw.bitCursor)
+
+	buff := make([]byte, 1)
+	n, err := imgrw.Read(buff)
+	require.Nil(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []byte{'d'}, buff)
+
+	pos, err = imgrw.Seek(-2, io.SeekEnd)
+	require.Nil(t, err)
+	require.EqualValues(t, 4, pos)
+
+	buff = make([]byte, 2)
+	n, err = imgrw.Read(buff)
+	require.Equal(t, 2, n)
+	require.Equal(t, []byte{'e', 'f'}, buff)
+
+	pos, err = imgrw.Seek(1, io.SeekCurrent)
+	require.Equal(t, ErrSeekOutOfRange, err)
+	require.EqualValues(t, 6, pos)
+
+	pos, err = imgrw.Seek(-7, io.SeekEnd)
+	require.Equal(t, ErrSeekNegative, err)
+	require.EqualValues(t, 6, pos)
+}
+
+func Test_ImageReadWriterYCbCr_ReadAt_WriteAt(t *testing.T) {
+	imgrw := NewImageReadWriterYCbCr(
+		image.NewYCbCr(image.Rect(0, 0, 3, 1), image.YCbCrSubsampleRatio444),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		PointReadWriterYCbCrSimple{},
+	)
+
+	n, err := imgrw.WriteAt([]byte("abc"), 1)
+	require.Nil(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = imgrw.WriteAt([]byte("xyz"), 5)
+	require.Equal(t, ErrImageReadWriterYCbCrOverflow, err)
+	require.Equal(t, 1, n)
+
+	buff := make([]byte, 4)
+	n, err = imgrw.ReadAt(buff, 3)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []byte{'c', 0, 'x'}, buff[:n])
+
+	n, err = imgrw.ReadAt(buff, 0)
+	require.Nil(t, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, []byte{0, 'a', 'b', 'c'}, buff)
+
+	require.Equal(t, image.Point{0, 0}, imgrw.gen.Current())
+}
+
+// ycbcrPlanesTestReadWriter stores 2 bits in luma and 2 bits in every chroma channel of a point
+type ycbcrPlanesTestReadWriter struct{}
+
+func (ycbcrPlanesTestReadWriter) ReadBits(c color.YCbCr, p image.Point) uint64 {
+	return uint64(c.Y&3)<<4 | uint64(c.Cb&3)<<2 | uint64(c.Cr&3)
+}
+
+func (ycbcrPlanesTestReadWriter) WriteBits(value uint64, src color.YCbCr, p image.Point) color.YCbCr {
+	return color.YCbCr{
+		Y:  src.Y&^3 | byte(value>>4&3),
+		Cb: src.Cb&^3 | byte(value>>2&3),
+		Cr: src.Cr&^3 | byte(value&3),
+	}
+}
+
+func (ycbcrPlanesTestReadWriter) Bits(_ image.Point) int {
+	return 6
+}
+
+func (ycbcrPlanesTestReadWriter) LumaBits(_ image.Point) int {
+	return 2
+}
+
+func (ycbcrPlanesTestReadWriter) ChromaBits(_ image.Point) int {
+	return 4
+}
+
+func Test_ImageReadWriterYCbCr_SubsampleRatios(t *testing.T) {
+	ratios := []image.YCbCrSubsampleRatio{
+		image.YCbCrSubsampleRatio444,
+		image.YCbCrSubsampleRatio422,
+		image.YCbCrSubsampleRatio420,
+		image.YCbCrSubsampleRatio440,
+		image.YCbCrSubsampleRatio411,
+		image.YCbCrSubsampleRatio410,
+	}
+	rects := []image.Rectangle{
+		image.Rect(0, 0, 16, 8),
+		image.Rect(1, 3, 14, 12),
+	}
+	prws := []PointReadWriterYCbCr{
+		PointReadWriterYCbCrSimple{},
+		PointReadWriterYCbCrLuma{},
+		ycbcrPlanesTestReadWriter{},
+	}
+
+	index := 0
+	for _, ratio := range ratios {
+		for _, rect := range rects {
+			for _, prw := range prws {
+				img := image.NewYCbCr(rect, ratio)
+
+				chromaSamples := map[int]bool{}
+				for y := rect.Min.Y; y < rect.Max.Y; y++ {
+					for x := rect.Min.X; x < rect.Max.X; x++ {
+						chromaSamples[img.COffset(x, y)] = true
+					}
+				}
+				luma, chroma := ycbcrPlanesBits(prw, image.Point{})
+				bits := luma*rect.Dx()*rect.Dy() + chroma*len(chromaSamples)
+
+				for _, gen := range []PointsSequenceGenerator{
+					NewSimplePointsSequenceGenerator(rect),
+					NewRandPointsSequenceGenerator(rect, []byte("secret")),
+				} {
+					imgrw := NewImageReadWriterYCbCr(img, gen, prw)
+					require.EqualValues(t, bits/8, imgrw.Size(), "Test index %d", index)
+
+					data := make([]byte, imgrw.Size())
+					_, err := rand.Read(data)
+					require.Nil(t, err, "Test index %d", index)
+					n, err := imgrw.Write(data)
+					require.Nil(t, err, "Test index %d", index)
+					require.Equal(t, len(data), n, "Test index %d", index)
+
+					actual := make([]byte, len(data))
+					n, err = imgrw.ReadAt(actual, 0)
+					require.Nil(t, err, "Test index %d", index)
+					require.Equal(t, len(data), n, "Test index %d", index)
+					require.Equal(t, data, actual, "Test index %d", index)
+
+					index++
+				}
+			}
+		}
+	}
+}
@@ -6,21 +6,23 @@ import (
 	"crypto/rand"
 	"image"
 	"image/color"
+	"image/draw"
 	"io"
+	"sync"
 	"testing"
 
 	"gopkg.in/stretchr/testify.v1/require"
 )
 
 func Test_Image_Write_UsePoint32(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 5, 5)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 5, 5)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 5),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 
 	n, err := img.Write([]byte("testing"))
 	require.Equal(t, 7, n)
@@ -40,14 +42,14 @@ func Test_Image_Write_UsePoint32(t *testing.T) {
 }
 
 func Test_Image_Write_UsePoint32HandleErrOverflowOnePoint(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 1, 1)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 1, 1)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 1, 1),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 
 	n, err := img.Write([]byte("testing"))
 	require.Equal(t, 4, n)
@@ -55,14 +57,14 @@ func Test_Image_Write_UsePoint32HandleErrOverflowOnePoint(t *testing.T) {
 }
 
 func Test_Image_Write_UsePoint32HandleErrOverflowManyPoints(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 10, 10)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 10, 10)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 10, 10),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 
 	size := img.Size() * 2
 	buff := make([]byte, size)
@@ -76,14 +78,14 @@ func Test_Image_Write_UsePoint32HandleErrOverflowManyPoints(t *testing.T) {
 }
 
 func Test_Image_Write_UsePoint64(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 5, 5)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 5, 5)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 5),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 
 	n, err := img.Write([]byte("testing"))
 	require.Equal(t, 7, n)
@@ -103,14 +105,14 @@ func Test_Image_Write_UsePoint64(t *testing.T) {
 }
 
 func Test_Image_Write_UsePoint64_ErrOverflowOnePoint(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 1, 1)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 1, 1)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 1, 1),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 
 	n, err := img.Write([]byte("testing 12345678"))
 	require.Equal(t, 8, n)
@@ -118,14 +120,14 @@ func Test_Image_Write_UsePoint64_ErrOverflowOnePoint(t *testing.T) {
 }
 
 func Test_Image_Write_UsePoint64HandleErrOverflowManyPoints(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 10, 10)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 10, 10)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 10, 10),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 
 	size := img.Size() * 2
 	buff := make([]byte, size)
@@ -139,14 +141,14 @@ func Test_Image_Write_UsePoint64HandleErrOverflowManyPoints(t *testing.T) {
 }
 
 func Test_Image_Read_UsePoint32(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 5, 5)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 5, 5)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 5),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 
 	img.img.Set(0, 0, &color.RGBA{0, 't', 'e', 's'})
 	img.img.Set(1, 0, &color.RGBA{0, 't', 0, 'i'})
@@ -161,14 +163,14 @@ func Test_Image_Read_UsePoint32(t *testing.T) {
 }
 
 func Test_Image_Read_UsePoint64(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 5, 5)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 5, 5)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 5, 5),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 
 	img.img.Set(0, 0, &color.RGBA64{0, 't' << 8, 'e', 's'})
 	img.img.Set(1, 0, &color.RGBA64{0, 't' << 8, 0, 'i'})
@@ -187,14 +189,14 @@ func Test_Image_Read_UsePoint64(t *testing.T) {
 }
 
 func Test_Image_ReadWrite32_Hash(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 100, 100)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 100)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 100, 100),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -205,9 +207,8 @@ func Test_Image_ReadWrite32_Hash(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -216,14 +217,14 @@ func Test_Image_ReadWrite32_Hash(t *testing.T) {
 }
 
 func Test_Image_ReadWrite32_Hash_NoSquare(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 100, 11)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 11)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 100, 11),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -234,9 +235,8 @@ func Test_Image_ReadWrite32_Hash_NoSquare(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -245,14 +245,14 @@ func Test_Image_ReadWrite32_Hash_NoSquare(t *testing.T) {
 }
 
 func Test_Image_ReadWrite64_Hash(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 100, 100)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 100, 100)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 100, 100),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -263,9 +263,8 @@ func Test_Image_ReadWrite64_Hash(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -274,14 +273,14 @@ func Test_Image_ReadWrite64_Hash(t *testing.T) {
 }
 
 func Test_Image_ReadWrite64_Hash_NoSquare(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(0, 0, 25, 100)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(0, 0, 25, 100)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 25, 100),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -292,9 +291,8 @@ func Test_Image_ReadWrite64_Hash_NoSquare(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -303,14 +301,14 @@ func Test_Image_ReadWrite64_Hash_NoSquare(t *testing.T) {
 }
 
 func Test_Image_ReadWrite16_Hash(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 100, 100)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 100)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 100, 100),
 			cursor: 0,
 		},
-		prw: GentlePoint16ReadWriter{},
-	}
+		GentlePoint16ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -321,9 +319,8 @@ func Test_Image_ReadWrite16_Hash(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -332,14 +329,253 @@ func Test_Image_ReadWrite16_Hash(t *testing.T) {
 }
 
 func Test_Image_ReadWrite16_Hash_NoSquare(t *testing.T) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(0, 0, 12, 100)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 12, 100)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(0, 0, 12, 100),
 			cursor: 0,
 		},
-		prw: GentlePoint16ReadWriter{},
+		GentlePoint16ReadWriter{},
+	)
+	hasher := md5.New()
+	buff := bytes.NewBuffer(nil)
+	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	firstSum := hasher.Sum(nil)
+	hasher.Reset()
+	n, err = buff.WriteTo(img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+	n, err = io.Copy(hasher, img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	secondSum := hasher.Sum(nil)
+	require.Equal(t, firstSum, secondSum)
+}
+
+func Test_Image_Seek_UsePoint32(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	img.img.Set(0, 0, &color.RGBA{'a', 'b', 'c', 'd'})
+	img.img.Set(1, 0, &color.RGBA{'e', 'f', 'g', 'h'})
+	img.img.Set(2, 0, &color.RGBA{'i', 'j', 'k', 'l'})
+
+	pos, err := img.Seek(6, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 6, pos)
+	require.Equal(t, image.Point{1, 0}, img.gen.Current())
+	require.Equal(t, 16, img.bitCursor)
+
+	buff := make([]byte, 2)
+	n, err := img.Read(buff)
+	require.Nil(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []byte{'g', 'h'}, buff)
+
+	pos, err = img.Seek(-3, io.SeekCurrent)
+	require.Nil(t, err)
+	require.EqualValues(t, 5, pos)
+	require.Equal(t, image.Point{1, 0}, img.gen.Current())
+	require.Equal(t, 8, img.bitCursor)
+
+	pos, err = img.Seek(-4, io.SeekEnd)
+	require.Nil(t, err)
+	require.EqualValues(t, 8, pos)
+	require.Equal(t, image.Point{2, 0}, img.gen.Current())
+	require.Equal(t, 0, img.bitCursor)
+
+	buff = make([]byte, 4)
+	n, err = img.Read(buff)
+	require.Equal(t, 4, n)
+	require.Equal(t, []byte{'i', 'j', 'k', 'l'}, buff)
+
+	pos, err = img.Seek(0, io.SeekEnd)
+	require.Nil(t, err)
+	require.EqualValues(t, 12, pos)
+	require.False(t, img.gen.Valid())
+
+	n, err = img.Read(buff)
+	require.Equal(t, 0, n)
+	require.Equal(t, io.EOF, err)
+}
+
+func Test_Image_Seek_UsePoint32_Write(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	pos, err := img.Seek(4, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 4, pos)
+
+	n, err := img.Write([]byte("test"))
+	require.Nil(t, err)
+	require.Equal(t, 4, n)
+
+	require.Equal(t, color.RGBA{}, img.img.At(0, 0))
+	require.Equal(t, color.RGBA{'t', 'e', 's', 't'}, img.img.At(1, 0))
+	require.Equal(t, color.RGBA{}, img.img.At(2, 0))
+
+	pos, err = img.Seek(0, io.SeekCurrent)
+	require.Nil(t, err)
+	require.EqualValues(t, 8, pos)
+}
+
+func Test_Image_Seek_ReturnsErrors(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	pos, err := img.Seek(5, io.SeekStart)
+	require.Nil(t, err)
+	require.EqualValues(t, 5, pos)
+
+	pos, err = img.Seek(-6, io.SeekCurrent)
+	require.Equal(t, ErrSeekNegative, err)
+	require.EqualValues(t, 5, pos)
+
+	pos, err = img.Seek(1, io.SeekEnd)
+	require.Equal(t, ErrSeekOutOfRange, err)
+	require.EqualValues(t, 5, pos)
+
+	pos, err = img.Seek(0, 42)
+	require.Equal(t, ErrSeekWhence, err)
+	require.EqualValues(t, 5, pos)
+
+	require.Equal(t, image.Point{1, 0}, img.gen.Current())
+	require.Equal(t, 8, img.bitCursor)
+}
+
+func Test_Image_ReadAt_UsePoint32(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	img.img.Set(0, 0, &color.RGBA{'a', 'b', 'c', 'd'})
+	img.img.Set(1, 0, &color.RGBA{'e', 'f', 'g', 'h'})
+	img.img.Set(2, 0, &color.RGBA{'i', 'j', 'k', 'l'})
+
+	tests := []struct {
+		offset int64
+		size   int
+
+		expectedData []byte
+		expectedErr  error
+	}{
+		{0, 12, []byte("abcdefghijkl"), nil},
+		{1, 2, []byte("bc"), nil},
+		{3, 6, []byte("defghi"), nil},
+		{10, 4, []byte("kl"), io.EOF},
+		{12, 1, []byte{}, io.EOF},
+		{-1, 1, []byte{}, ErrSeekNegative},
+	}
+
+	for i, test := range tests {
+		buff := make([]byte, test.size)
+		n, err := img.ReadAt(buff, test.offset)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		require.Equal(t, test.expectedData, buff[:n], "Test index %d", i)
+	}
+
+	require.Equal(t, image.Point{0, 0}, img.gen.Current())
+}
+
+func Test_Image_WriteAt_UsePoint32(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 3, 1),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	n, err := img.WriteAt([]byte("test"), 2)
+	require.Nil(t, err)
+	require.Equal(t, 4, n)
+
+	n, err = img.WriteAt([]byte("xyz"), 10)
+	require.Equal(t, ErrOverflow, err)
+	require.Equal(t, 2, n)
+
+	require.Equal(t, color.RGBA{0, 0, 't', 'e'}, img.img.At(0, 0))
+	require.Equal(t, color.RGBA{'s', 't', 0, 0}, img.img.At(1, 0))
+	require.Equal(t, color.RGBA{0, 0, 'x', 'y'}, img.img.At(2, 0))
+	require.Equal(t, image.Point{0, 0}, img.gen.Current())
+}
+
+func Test_Image_ReadAt_Parallel(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 100)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 100, 100),
+			cursor: 0,
+		},
+		SimplePoint32ReadWriter{},
+	)
+
+	data := make([]byte, img.Size())
+	_, err := rand.Reader.Read(data)
+	require.Nil(t, err)
+	n, err := img.WriteAt(data, 0)
+	require.Nil(t, err)
+	require.Equal(t, len(data), n)
+
+	const (
+		workers   = 8
+		chunkSize = 5000
+	)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, workers)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w] = make([]byte, chunkSize)
+			img.ReadAt(results[w], int64(w*chunkSize))
+		}(w)
+	}
+
+	wg.Wait()
+
+	for w := 0; w < workers; w++ {
+		require.Equal(t, data[w*chunkSize:(w+1)*chunkSize], results[w], "Worker %d", w)
 	}
+}
+
+func Test_Image_ReadWrite32_Hash_RandPoints(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 11)),
+		NewRandPointsSequenceGenerator(image.Rect(0, 0, 100, 11), []byte("secret")),
+		SimplePoint32ReadWriter{},
+	)
 	hasher := md5.New()
 	buff := bytes.NewBuffer(nil)
 	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
@@ -350,9 +586,8 @@ func Test_Image_ReadWrite16_Hash_NoSquare(t *testing.T) {
 	n, err = buff.WriteTo(img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
-	img.gen.Rewind()
-	img.byteCursor = 0
-	require.True(t, img.gen.Valid())
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
 	n, err = io.Copy(hasher, img)
 	require.Nil(t, err)
 	require.Equal(t, img.Size(), n)
@@ -360,15 +595,211 @@ func Test_Image_ReadWrite16_Hash_NoSquare(t *testing.T) {
 	require.Equal(t, firstSum, secondSum)
 }
 
+func Test_Image_ReadWrite8_Hash(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 100, 100)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 100, 100),
+			cursor: 0,
+		},
+		SmartPoint8ReadWriter{},
+	)
+	hasher := md5.New()
+	buff := bytes.NewBuffer(nil)
+	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	firstSum := hasher.Sum(nil)
+	hasher.Reset()
+	n, err = buff.WriteTo(img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+	n, err = io.Copy(hasher, img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	secondSum := hasher.Sum(nil)
+	require.Equal(t, firstSum, secondSum)
+}
+
+func Test_Image_ReadWrite8_Hash_NoSquare(t *testing.T) {
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 13, 100)),
+		&SimplePointsSequenceGenerator{
+			rect:   image.Rect(0, 0, 13, 100),
+			cursor: 0,
+		},
+		SmartPoint8ReadWriter{},
+	)
+	hasher := md5.New()
+	buff := bytes.NewBuffer(nil)
+	n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	firstSum := hasher.Sum(nil)
+	hasher.Reset()
+	n, err = buff.WriteTo(img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	_, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+	n, err = io.Copy(hasher, img)
+	require.Nil(t, err)
+	require.Equal(t, img.Size(), n)
+	secondSum := hasher.Sum(nil)
+	require.Equal(t, firstSum, secondSum)
+}
+
+func Test_Image_Write8_KeepsColorsClose(t *testing.T) {
+	rect := image.Rect(0, 0, 50, 50)
+	src := image.NewRGBA(rect)
+	_, err := rand.Reader.Read(src.Pix)
+	require.Nil(t, err)
+	for i := 3; i < len(src.Pix); i += 4 {
+		src.Pix[i] = 0xff
+	}
+	dst := image.NewRGBA(rect)
+	copy(dst.Pix, src.Pix)
+
+	img := NewImage(dst, NewSimplePointsSequenceGenerator(rect), SmartPoint8ReadWriter{})
+	_, err = io.CopyN(img, rand.Reader, img.Size())
+	require.Nil(t, err)
+
+	for i := range src.Pix {
+		diff := int(src.Pix[i]) - int(dst.Pix[i])
+		require.True(t, diff > -8 && diff < 8, "Pix index %d", i)
+	}
+}
+
+func Test_Image_ChannelBits_ReadWrite_Hash(t *testing.T) {
+	tests := []struct {
+		img  draw.Image
+		bits int
+		mask ChannelMask
+	}{
+		{image.NewNRGBA(image.Rect(0, 0, 100, 100)), 1, ChannelRGBA},
+		{image.NewRGBA(image.Rect(0, 0, 100, 11)), 1, ChannelRGB},
+		{image.NewRGBA(image.Rect(0, 0, 13, 100)), 3, ChannelRGB},
+		{image.NewRGBA(image.Rect(0, 0, 17, 23)), 5, ChannelR | ChannelB},
+		{image.NewNRGBA(image.Rect(0, 0, 100, 100)), 8, ChannelRGBA},
+		{image.NewRGBA64(image.Rect(0, 0, 31, 7)), 11, ChannelRGB},
+		{image.NewNRGBA64(image.Rect(0, 0, 100, 100)), 16, ChannelRGBA},
+	}
+
+	for i, test := range tests {
+		prw, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Nil(t, err, "Test index %d", i)
+		img := NewImage(test.img, NewSimplePointsSequenceGenerator(test.img.Bounds()), prw)
+
+		rect := test.img.Bounds()
+		require.EqualValues(t, rect.Dx()*rect.Dy()*prw.Bits(image.Point{})/8, img.Size(), "Test index %d", i)
+
+		hasher := md5.New()
+		buff := bytes.NewBuffer(nil)
+		n, err := buff.ReadFrom(io.TeeReader(io.LimitReader(rand.Reader, img.Size()), hasher))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, img.Size(), n, "Test index %d", i)
+		firstSum := hasher.Sum(nil)
+		hasher.Reset()
+		n, err = buff.WriteTo(img)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, img.Size(), n, "Test index %d", i)
+		pos, err := img.Seek(0, io.SeekStart)
+		require.Nil(t, err, "Test index %d", i)
+		require.EqualValues(t, 0, pos, "Test index %d", i)
+		n, err = io.Copy(hasher, img)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, img.Size(), n, "Test index %d", i)
+		secondSum := hasher.Sum(nil)
+		require.Equal(t, firstSum, secondSum, "Test index %d", i)
+	}
+}
+
+func Test_Image_ChannelBits_Write_PacksBitsAcrossPoints(t *testing.T) {
+	prw, err := NewChannelBitsReadWriter(1, ChannelRGB)
+	require.Nil(t, err)
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 3, 1)),
+		NewSimplePointsSequenceGenerator(image.Rect(0, 0, 3, 1)),
+		prw,
+	)
+	require.EqualValues(t, 1, img.Size())
+
+	n, err := img.Write([]byte{0xa5, 0xff})
+	require.Equal(t, 1, n)
+	require.Equal(t, ErrOverflow, err)
+
+	require.Equal(t, color.RGBA{1, 0, 1, 0}, img.img.At(0, 0))
+	require.Equal(t, color.RGBA{0, 0, 1, 0}, img.img.At(1, 0))
+	require.Equal(t, color.RGBA{0, 1, 1, 0}, img.img.At(2, 0))
+
+	buff := make([]byte, 2)
+	n, err = img.ReadAt(buff, 0)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, byte(0xa5), buff[0])
+}
+
+func Test_Image_ChannelBits_ReadAt_WriteAt_Seek(t *testing.T) {
+	prw, err := NewChannelBitsReadWriter(3, ChannelRGB)
+	require.Nil(t, err)
+	img := NewImage(
+		image.NewRGBA(image.Rect(0, 0, 10, 3)),
+		NewSimplePointsSequenceGenerator(image.Rect(0, 0, 10, 3)),
+		prw,
+	)
+	require.EqualValues(t, 33, img.Size())
+
+	data := []byte("abcdefghijklmnopqrstuvwxyz0123456")
+	n, err := img.WriteAt(data, 0)
+	require.Nil(t, err)
+	require.Equal(t, len(data), n)
+
+	n, err = img.WriteAt([]byte("XYZ"), 7)
+	require.Nil(t, err)
+	require.Equal(t, 3, n)
+	copy(data[7:], "XYZ")
+
+	buff := make([]byte, 5)
+	n, err = img.ReadAt(buff, 5)
+	require.Nil(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, data[5:10], buff)
+
+	pos, err := img.Seek(-4, io.SeekEnd)
+	require.Nil(t, err)
+	require.EqualValues(t, 29, pos)
+
+	buff = make([]byte, 10)
+	n, err = img.Read(buff)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, data[29:], buff[:n])
+
+	pos, err = img.Seek(0, io.SeekStart)
+	require.Nil(t, err)
+	buff = make([]byte, len(data))
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		n, _ = img.Read(buff[i:end])
+		require.Equal(t, end-i, n)
+	}
+	require.Equal(t, data, buff)
+}
+
 func WriteBytesToImage64(x0, y0, x1, y1 int) (int64, error) {
-	img := &rwImage{
-		img: image.NewRGBA64(image.Rect(x0, y0, x1, y1)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA64(image.Rect(x0, y0, x1, y1)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(x0, y0, x1, y1),
 			cursor: 0,
 		},
-		prw: SimplePoint64ReadWriter{},
-	}
+		SimplePoint64ReadWriter{},
+	)
 	return io.CopyN(img, rand.Reader, img.Size())
 }
 
@@ -382,14 +813,14 @@ func Benchmark_WriteBytesToImage64(b *testing.B) {
 }
 
 func WriteBytesToImage32(x0, y0, x1, y1 int) (int64, error) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(x0, y0, x1, y1)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(x0, y0, x1, y1)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(x0, y0, x1, y1),
 			cursor: 0,
 		},
-		prw: SimplePoint32ReadWriter{},
-	}
+		SimplePoint32ReadWriter{},
+	)
 	return io.CopyN(img, rand.Reader, img.Size())
 }
 
@@ -403,14 +834,14 @@ func Benchmark_WriteBytesToImage32(b *testing.B) {
 }
 
 func WriteBytesToImage16(x0, y0, x1, y1 int) (int64, error) {
-	img := &rwImage{
-		img: image.NewRGBA(image.Rect(x0, y0, x1, y1)),
-		gen: &SimplePointsSequenceGenerator{
+	img := NewImage(
+		image.NewRGBA(image.Rect(x0, y0, x1, y1)),
+		&SimplePointsSequenceGenerator{
 			rect:   image.Rect(x0, y0, x1, y1),
 			cursor: 0,
 		},
-		prw: GentlePoint16ReadWriter{},
-	}
+		GentlePoint16ReadWriter{},
+	)
 	return io.CopyN(img, rand.Reader, img.Size())
 }
 
@@ -0,0 +1,495 @@
+package imgio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	jpegMarkerSOF0 = 0xc0
+	jpegMarkerSOF1 = 0xc1
+	jpegMarkerDHT  = 0xc4
+	jpegMarkerSOI  = 0xd8
+	jpegMarkerEOI  = 0xd9
+	jpegMarkerSOS  = 0xda
+	jpegMarkerDQT  = 0xdb
+	jpegMarkerDRI  = 0xdd
+	jpegMarkerAPP0 = 0xe0
+	jpegMarkerAPPF = 0xef
+	jpegMarkerCOM  = 0xfe
+
+	jpegBlockSize = 64
+	jpegMaxTables = 4
+)
+
+// jpegBlock is block of quantized DCT coefficients in zigzag order
+type jpegBlock [jpegBlockSize]int32
+
+type jpegComponent struct {
+	id     byte
+	h, v   int
+	tq     byte
+	blocks []jpegBlock
+	// Number of blocks in a row and a column
+	width, height int
+}
+
+// JPEGCoefficients holds quantized DCT coefficients of baseline JPEG. Coefficients can be
+// modified and encoded back without decompression, so quantization doesn't affect them
+type JPEGCoefficients struct {
+	width, height int
+	components    []jpegComponent
+	hMax, vMax    int
+
+	// Segments which are kept as is: quantization tables, application data and comments
+	segments [][]byte
+
+	// usable are coefficients available for embedding: AC coefficients which aren't equal to 0
+	// and 1. X is index of a coefficient among coefficients of component Y
+	usable []image.Point
+}
+
+// DecodeJPEGCoefficients reads baseline JPEG from r
+func DecodeJPEGCoefficients(r io.Reader) (*JPEGCoefficients, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 2 || data[0] != 0xff || data[1] != jpegMarkerSOI {
+		return nil, ErrJPEGFormat
+	}
+
+	var (
+		c               = &JPEGCoefficients{}
+		dcTables        [jpegMaxTables]*jpegHuffmanDecoder
+		acTables        [jpegMaxTables]*jpegHuffmanDecoder
+		restartInterval int
+	)
+
+	for pos := 2; ; {
+		if pos+1 >= len(data) || data[pos] != 0xff {
+			return nil, ErrJPEGFormat
+		}
+
+		marker := data[pos+1]
+		pos += 2
+
+		switch {
+		case marker == 0xff:
+			// Fill byte
+			pos--
+			continue
+		case marker == jpegMarkerEOI:
+			if c.components == nil {
+				return nil, ErrJPEGFormat
+			}
+			c.findUsable()
+			return c, nil
+		case marker >= 0xd0 && marker <= 0xd7 || marker == 0x01:
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, ErrJPEGFormat
+		}
+		length := int(binary.BigEndian.Uint16(data[pos:]))
+		if length < 2 || pos+length > len(data) {
+			return nil, ErrJPEGFormat
+		}
+		segment := data[pos+2 : pos+length]
+		raw := data[pos-2 : pos+length]
+		pos += length
+
+		switch {
+		case marker == jpegMarkerSOF0 || marker == jpegMarkerSOF1:
+			if c.components != nil {
+				return nil, ErrJPEGFormat
+			}
+			if err := c.parseFrame(segment); err != nil {
+				return nil, err
+			}
+		case marker >= 0xc0 && marker <= 0xcf && marker != jpegMarkerDHT && marker != 0xc8 && marker != 0xcc:
+			// Progressive, lossless, hierarchical and arithmetic coding
+			return nil, ErrJPEGUnsupported
+		case marker == jpegMarkerDHT:
+			for len(segment) > 0 {
+				if len(segment) < 17 {
+					return nil, ErrJPEGFormat
+				}
+
+				class, id := segment[0]>>4, segment[0]&0x0f
+				if class > 1 || id >= jpegMaxTables {
+					return nil, ErrJPEGFormat
+				}
+
+				var spec jpegHuffmanSpec
+				copy(spec.counts[:], segment[1:17])
+				total := 0
+				for _, count := range spec.counts {
+					total += int(count)
+				}
+				if len(segment) < 17+total {
+					return nil, ErrJPEGFormat
+				}
+				spec.symbols = segment[17 : 17+total]
+
+				decoder, err := newJPEGHuffmanDecoder(spec)
+				if err != nil {
+					return nil, err
+				}
+				if class == 0 {
+					dcTables[id] = decoder
+				} else {
+					acTables[id] = decoder
+				}
+
+				segment = segment[17+total:]
+			}
+		case marker == jpegMarkerDRI:
+			if len(segment) != 2 {
+				return nil, ErrJPEGFormat
+			}
+			restartInterval = int(binary.BigEndian.Uint16(segment))
+		case marker == jpegMarkerSOS:
+			if c.components == nil {
+				return nil, ErrJPEGFormat
+			}
+			n, err := c.decodeScan(segment, data[pos:], &dcTables, &acTables, restartInterval)
+			if err != nil {
+				return nil, err
+			}
+			pos += n
+		case marker == jpegMarkerDQT || marker >= jpegMarkerAPP0 && marker <= jpegMarkerAPPF || marker == jpegMarkerCOM:
+			c.segments = append(c.segments, raw)
+		}
+	}
+}
+
+func (c *JPEGCoefficients) parseFrame(segment []byte) error {
+	if len(segment) < 6 {
+		return ErrJPEGFormat
+	}
+
+	if segment[0] != 8 {
+		return ErrJPEGUnsupported
+	}
+
+	c.height = int(binary.BigEndian.Uint16(segment[1:]))
+	c.width = int(binary.BigEndian.Uint16(segment[3:]))
+	count := int(segment[5])
+
+	if c.width == 0 || c.height == 0 || count == 0 || count > 4 || len(segment) != 6+count*3 {
+		return ErrJPEGFormat
+	}
+
+	c.components = make([]jpegComponent, count)
+	c.hMax, c.vMax = 1, 1
+
+	for i := range c.components {
+		comp := segment[6+i*3:]
+		c.components[i] = jpegComponent{
+			id: comp[0],
+			h:  int(comp[1] >> 4),
+			v:  int(comp[1] & 0x0f),
+			tq: comp[2],
+		}
+		if c.components[i].h < 1 || c.components[i].h > 4 || c.components[i].v < 1 || c.components[i].v > 4 {
+			return ErrJPEGFormat
+		}
+		if count == 1 {
+			// Single component is never interleaved, sampling factors don't matter
+			c.components[i].h, c.components[i].v = 1, 1
+		}
+		if c.components[i].h > c.hMax {
+			c.hMax = c.components[i].h
+		}
+		if c.components[i].v > c.vMax {
+			c.vMax = c.components[i].v
+		}
+	}
+
+	mcusX, mcusY := c.mcus()
+	for i := range c.components {
+		comp := &c.components[i]
+		comp.width = mcusX * comp.h
+		comp.height = mcusY * comp.v
+		comp.blocks = make([]jpegBlock, comp.width*comp.height)
+	}
+
+	return nil
+}
+
+// mcus returns number of interleaved minimum coded units in a row and a column
+func (c *JPEGCoefficients) mcus() (int, int) {
+	return (c.width + 8*c.hMax - 1) / (8 * c.hMax), (c.height + 8*c.vMax - 1) / (8 * c.vMax)
+}
+
+// visibleBlocks returns number of blocks of component which cover the image as coded in
+// non-interleaved scan
+func (c *JPEGCoefficients) visibleBlocks(comp *jpegComponent) (int, int) {
+	w := (c.width*comp.h + c.hMax - 1) / c.hMax
+	h := (c.height*comp.v + c.vMax - 1) / c.vMax
+	return (w + 7) / 8, (h + 7) / 8
+}
+
+// scanOrder calls f for every block of components in the order of the scan. It is called with
+// true restart flag before blocks of every restart interval except the first one
+func (c *JPEGCoefficients) scanOrder(comps []int, restartInterval int, f func(comp int, block *jpegBlock, restart bool) error) error {
+	var mcus, mcusX int
+	if len(comps) == 1 {
+		w, h := c.visibleBlocks(&c.components[comps[0]])
+		mcus, mcusX = w*h, w
+	} else {
+		x, y := c.mcus()
+		mcus, mcusX = x*y, x
+	}
+
+	for mcu := 0; mcu < mcus; mcu++ {
+		restart := restartInterval > 0 && mcu > 0 && mcu%restartInterval == 0
+		mx, my := mcu%mcusX, mcu/mcusX
+
+		for _, index := range comps {
+			comp := &c.components[index]
+
+			if len(comps) == 1 {
+				if err := f(index, &comp.blocks[my*comp.width+mx], restart); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for v := 0; v < comp.v; v++ {
+				for h := 0; h < comp.h; h++ {
+					block := &comp.blocks[(my*comp.v+v)*comp.width+mx*comp.h+h]
+					if err := f(index, block, restart); err != nil {
+						return err
+					}
+					restart = false
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeScan decodes entropy-coded segment of scan from data and returns its length
+func (c *JPEGCoefficients) decodeScan(header, data []byte, dcTables, acTables *[jpegMaxTables]*jpegHuffmanDecoder, restartInterval int) (int, error) {
+	if len(header) < 1 || len(header) != 1+int(header[0])*2+3 || header[0] == 0 {
+		return 0, ErrJPEGFormat
+	}
+
+	count := int(header[0])
+	comps := make([]int, count)
+	dc := make([]*jpegHuffmanDecoder, len(c.components))
+	ac := make([]*jpegHuffmanDecoder, len(c.components))
+
+	for i := 0; i < count; i++ {
+		id, tables := header[1+i*2], header[2+i*2]
+
+		comps[i] = -1
+		for j := range c.components {
+			if c.components[j].id == id {
+				comps[i] = j
+			}
+		}
+		if comps[i] < 0 || tables>>4 >= jpegMaxTables || tables&0x0f >= jpegMaxTables {
+			return 0, ErrJPEGFormat
+		}
+
+		dc[comps[i]], ac[comps[i]] = dcTables[tables>>4], acTables[tables&0x0f]
+		if dc[comps[i]] == nil || ac[comps[i]] == nil {
+			return 0, ErrJPEGFormat
+		}
+	}
+
+	if ss, se, a := header[len(header)-3], header[len(header)-2], header[len(header)-1]; ss != 0 || se != 63 || a != 0 {
+		return 0, ErrJPEGUnsupported
+	}
+
+	r := &jpegBitReader{data: data}
+	pred := make([]int32, len(c.components))
+
+	err := c.scanOrder(comps, restartInterval, func(comp int, block *jpegBlock, restart bool) error {
+		if restart {
+			if err := r.restart(); err != nil {
+				return err
+			}
+			for i := range pred {
+				pred[i] = 0
+			}
+		}
+
+		s, err := dc[comp].decode(r)
+		if err != nil {
+			return err
+		}
+		if s > 11 {
+			return ErrJPEGFormat
+		}
+		diff, err := r.receiveExtend(s)
+		if err != nil {
+			return err
+		}
+		pred[comp] += diff
+		block[0] = pred[comp]
+
+		for k := 1; k < jpegBlockSize; k++ {
+			rs, err := ac[comp].decode(r)
+			if err != nil {
+				return err
+			}
+
+			run, s := int(rs>>4), rs&0x0f
+			if s == 0 {
+				if run != 15 {
+					break
+				}
+				k += 15
+				continue
+			}
+
+			k += run
+			if k >= jpegBlockSize {
+				return ErrJPEGFormat
+			}
+			if block[k], err = r.receiveExtend(s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Entropy-coded segment ends with the first marker which is not a restart marker
+	pos := r.pos
+	for pos+1 < len(data) {
+		if data[pos] == 0xff && data[pos+1] != 0x00 && data[pos+1] != 0xff && (data[pos+1] < 0xd0 || data[pos+1] > 0xd7) {
+			return pos, nil
+		}
+		pos++
+	}
+
+	return 0, ErrJPEGFormat
+}
+
+func (c *JPEGCoefficients) findUsable() {
+	c.usable = c.usable[:0]
+
+	for y := range c.components {
+		for b := range c.components[y].blocks {
+			for k := 1; k < jpegBlockSize; k++ {
+				if v := c.components[y].blocks[b][k]; v != 0 && v != 1 {
+					c.usable = append(c.usable, image.Point{X: b*jpegBlockSize + k, Y: y})
+				}
+			}
+		}
+	}
+}
+
+// Bounds returns rectangle of usable coefficients. It has the height 1 and the width equal to
+// number of coefficients, so that points sequence generators can be used to order coefficients
+func (c *JPEGCoefficients) Bounds() image.Rectangle {
+	return image.Rect(0, 0, len(c.usable), 1)
+}
+
+// coefficient returns usable coefficient on point p of Bounds
+func (c *JPEGCoefficients) coefficient(p image.Point) *int32 {
+	coef := c.usable[p.X]
+	return &c.components[coef.Y].blocks[coef.X/jpegBlockSize][coef.X%jpegBlockSize]
+}
+
+// Encode writes baseline JPEG with the coefficients to w. Huffman tables of the original image
+// are replaced with the standard ones, restart markers are omitted
+func (c *JPEGCoefficients) Encode(w io.Writer) error {
+	buff := bytes.NewBuffer(nil)
+	buff.Write([]byte{0xff, jpegMarkerSOI})
+
+	for _, segment := range c.segments {
+		buff.Write(segment)
+	}
+
+	writeSegment := func(marker byte, segment []byte) {
+		buff.Write([]byte{0xff, marker})
+		binary.Write(buff, binary.BigEndian, uint16(len(segment)+2))
+		buff.Write(segment)
+	}
+
+	frame := []byte{8, byte(c.height >> 8), byte(c.height), byte(c.width >> 8), byte(c.width), byte(len(c.components))}
+	for _, comp := range c.components {
+		frame = append(frame, comp.id, byte(comp.h<<4|comp.v), comp.tq)
+	}
+	writeSegment(jpegMarkerSOF0, frame)
+
+	var tables []byte
+	for i, spec := range jpegStandardHuffmanSpecs {
+		tables = append(tables, byte(i%2<<4|i/2))
+		tables = append(tables, spec.counts[:]...)
+		tables = append(tables, spec.symbols...)
+	}
+	writeSegment(jpegMarkerDHT, tables)
+
+	scan := []byte{byte(len(c.components))}
+	comps := make([]int, len(c.components))
+	for i, comp := range c.components {
+		table := byte(0)
+		if i > 0 {
+			table = 1
+		}
+		scan = append(scan, comp.id, table<<4|table)
+		comps[i] = i
+	}
+	scan = append(scan, 0, 63, 0)
+	writeSegment(jpegMarkerSOS, scan)
+
+	var encoders [jpegMaxTables]*jpegHuffmanEncoder
+	for i, spec := range jpegStandardHuffmanSpecs {
+		encoders[i] = newJPEGHuffmanEncoder(spec)
+	}
+
+	bw := &jpegBitWriter{}
+	pred := make([]int32, len(c.components))
+
+	c.scanOrder(comps, 0, func(comp int, block *jpegBlock, _ bool) error {
+		dc, ac := encoders[0], encoders[1]
+		if comp > 0 {
+			dc, ac = encoders[2], encoders[3]
+		}
+
+		bw.writeValue(dc, 0, block[0]-pred[comp])
+		pred[comp] = block[0]
+
+		run := uint8(0)
+		for k := 1; k < jpegBlockSize; k++ {
+			if block[k] == 0 {
+				run++
+				continue
+			}
+			for run > 15 {
+				ac.encode(bw, 0xf0)
+				run -= 16
+			}
+			bw.writeValue(ac, run, block[k])
+			run = 0
+		}
+		if run > 0 {
+			ac.encode(bw, 0x00)
+		}
+
+		return nil
+	})
+
+	bw.flush()
+	buff.Write(bw.data)
+	buff.Write([]byte{0xff, jpegMarkerEOI})
+
+	_, err := w.Write(buff.Bytes())
+	return err
+}
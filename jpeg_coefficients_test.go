@@ -0,0 +1,133 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+// newNoiseJPEG returns JPEG of image img filled with deterministic noise
+func newNoiseJPEG(img draw.Image, quality int) []byte {
+	random := rand.New(rand.NewSource(1))
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.Set(x, y, color.RGBA{uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256)), 0xff})
+		}
+	}
+
+	buff := bytes.NewBuffer(nil)
+	if err := jpeg.Encode(buff, img, &jpeg.Options{Quality: quality}); err != nil {
+		panic(err)
+	}
+	return buff.Bytes()
+}
+
+func Test_JPEGCoefficients_Encode(t *testing.T) {
+	tests := []struct {
+		img     draw.Image
+		quality int
+	}{
+		{image.NewRGBA(image.Rect(0, 0, 64, 64)), 90},
+		{image.NewRGBA(image.Rect(0, 0, 17, 13)), 50},
+		{image.NewRGBA(image.Rect(0, 0, 1, 1)), 100},
+		{image.NewGray(image.Rect(0, 0, 33, 9)), 75},
+		{image.NewGray(image.Rect(0, 0, 8, 8)), 10},
+	}
+
+	for i, test := range tests {
+		original := newNoiseJPEG(test.img, test.quality)
+
+		coeffs, err := DecodeJPEGCoefficients(bytes.NewReader(original))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.img.Bounds().Dx(), coeffs.width, "Test index %d", i)
+		require.Equal(t, test.img.Bounds().Dy(), coeffs.height, "Test index %d", i)
+
+		encoded := bytes.NewBuffer(nil)
+		require.Nil(t, coeffs.Encode(encoded), "Test index %d", i)
+
+		expected, err := jpeg.Decode(bytes.NewReader(original))
+		require.Nil(t, err, "Test index %d", i)
+		actual, err := jpeg.Decode(encoded)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, expected, actual, "Test index %d", i)
+	}
+}
+
+func Test_JPEGCoefficients_Encode_Restart(t *testing.T) {
+	// The image has restart interval of 2 MCUs
+	original, err := ioutil.ReadFile("testdata/restart.jpeg")
+	require.Nil(t, err)
+
+	coeffs, err := DecodeJPEGCoefficients(bytes.NewReader(original))
+	require.Nil(t, err)
+
+	encoded := bytes.NewBuffer(nil)
+	require.Nil(t, coeffs.Encode(encoded))
+
+	expected, err := jpeg.Decode(bytes.NewReader(original))
+	require.Nil(t, err)
+	actual, err := jpeg.Decode(encoded)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func Test_JPEGCoefficients_Usable(t *testing.T) {
+	coeffs, err := DecodeJPEGCoefficients(bytes.NewReader(newNoiseJPEG(image.NewRGBA(image.Rect(0, 0, 32, 32)), 90)))
+	require.Nil(t, err)
+
+	bounds := coeffs.Bounds()
+	require.Equal(t, 1, bounds.Dy())
+	require.True(t, bounds.Dx() > 0)
+
+	for x := 0; x < bounds.Dx(); x++ {
+		v := *coeffs.coefficient(image.Point{X: x})
+		require.True(t, v != 0 && v != 1)
+	}
+
+	// Blank image has no usable coefficients
+	coeffs, err = DecodeJPEGCoefficients(bytes.NewReader(newBlankJPEG(image.Rect(0, 0, 16, 16))))
+	require.Nil(t, err)
+	require.True(t, coeffs.Bounds().Empty())
+}
+
+func newBlankJPEG(rect image.Rectangle) []byte {
+	buff := bytes.NewBuffer(nil)
+	if err := jpeg.Encode(buff, image.NewGray(rect), nil); err != nil {
+		panic(err)
+	}
+	return buff.Bytes()
+}
+
+func Test_DecodeJPEGCoefficients_Errors(t *testing.T) {
+	valid := newBlankJPEG(image.Rect(0, 0, 8, 8))
+
+	// Progressive frame header instead of baseline one
+	progressive := append([]byte{}, valid...)
+	sof := bytes.Index(progressive, []byte{0xff, jpegMarkerSOF0})
+	progressive[sof+1] = 0xc2
+
+	tests := []struct {
+		data        []byte
+		expectedErr error
+	}{
+		{nil, ErrJPEGFormat},
+		{[]byte("not a jpeg"), ErrJPEGFormat},
+		{valid[:len(valid)/2], ErrJPEGFormat},
+		{valid[:len(valid)-2], ErrJPEGFormat},
+		{progressive, ErrJPEGUnsupported},
+		{valid, nil},
+	}
+
+	for i, test := range tests {
+		_, err := DecodeJPEGCoefficients(bytes.NewReader(test.data))
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+	}
+}
@@ -0,0 +1,275 @@
+package imgio
+
+import "errors"
+
+var (
+	ErrJPEGFormat      = errors.New("Invalid JPEG format")
+	ErrJPEGUnsupported = errors.New("Unsupported JPEG format")
+)
+
+// jpegHuffmanSpec is Huffman table in the form of DHT segment: number of codes of every length
+// from 1 to 16 bits and symbols ordered by code
+type jpegHuffmanSpec struct {
+	counts  [16]byte
+	symbols []byte
+}
+
+// jpegStandardHuffmanSpecs are Huffman tables from section K.3 of the JPEG specification: DC
+// and AC tables of luminance, DC and AC tables of chrominance. The AC tables contain every
+// symbol of baseline JPEG, so any quantized coefficients can be encoded with them
+var jpegStandardHuffmanSpecs = [4]jpegHuffmanSpec{
+	{
+		[16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125},
+		[]byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+			0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+			0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+			0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+			0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+			0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+			0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+			0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+			0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+			0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+			0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+			0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+			0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+			0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+	{
+		[16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119},
+		[]byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+			0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+			0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+			0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+			0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+			0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+			0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+			0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+			0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+			0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+			0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+			0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+			0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+			0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+			0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+			0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+}
+
+// jpegHuffmanDecoder decodes symbols with canonical Huffman codes as described in section F.2.2.3
+// of the JPEG specification
+type jpegHuffmanDecoder struct {
+	maxCode [17]int32
+	minCode [17]int32
+	valPtr  [17]int32
+	symbols []byte
+}
+
+func newJPEGHuffmanDecoder(spec jpegHuffmanSpec) (*jpegHuffmanDecoder, error) {
+	total := 0
+	for _, count := range spec.counts {
+		total += int(count)
+	}
+	if total != len(spec.symbols) || total > 256 {
+		return nil, ErrJPEGFormat
+	}
+
+	d := &jpegHuffmanDecoder{symbols: spec.symbols}
+
+	var code, index int32
+	for length := 1; length <= 16; length++ {
+		count := int32(spec.counts[length-1])
+		d.valPtr[length] = index
+		d.minCode[length] = code
+		d.maxCode[length] = code + count - 1
+		if count == 0 {
+			d.maxCode[length] = -1
+		}
+		code = (code + count) << 1
+		index += count
+	}
+
+	return d, nil
+}
+
+func (d *jpegHuffmanDecoder) decode(r *jpegBitReader) (byte, error) {
+	var code int32
+	for length := 1; length <= 16; length++ {
+		bit, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		code = code<<1 | int32(bit)
+		if code <= d.maxCode[length] {
+			return d.symbols[d.valPtr[length]+code-d.minCode[length]], nil
+		}
+	}
+	return 0, ErrJPEGFormat
+}
+
+// jpegHuffmanEncoder maps symbols to canonical Huffman codes
+type jpegHuffmanEncoder struct {
+	codes   [256]uint16
+	lengths [256]uint8
+}
+
+func newJPEGHuffmanEncoder(spec jpegHuffmanSpec) *jpegHuffmanEncoder {
+	e := &jpegHuffmanEncoder{}
+
+	var code uint16
+	index := 0
+	for length := 1; length <= 16; length++ {
+		for i := 0; i < int(spec.counts[length-1]); i++ {
+			symbol := spec.symbols[index]
+			e.codes[symbol] = code
+			e.lengths[symbol] = uint8(length)
+			code++
+			index++
+		}
+		code <<= 1
+	}
+
+	return e
+}
+
+func (e *jpegHuffmanEncoder) encode(w *jpegBitWriter, symbol byte) {
+	w.writeBits(uint32(e.codes[symbol]), uint(e.lengths[symbol]))
+}
+
+// jpegBitReader reads bits of entropy-coded segment removing stuffed zero bytes. When a marker is
+// reached, zero bits are returned until the reader is restarted
+type jpegBitReader struct {
+	data   []byte
+	pos    int
+	acc    byte
+	n      uint
+	marker bool
+}
+
+func (r *jpegBitReader) readBit() (uint8, error) {
+	if r.n == 0 {
+		if r.pos >= len(r.data) {
+			return 0, ErrJPEGFormat
+		}
+
+		r.acc = 0
+		if !r.marker {
+			b := r.data[r.pos]
+			if b != 0xff {
+				r.acc = b
+				r.pos++
+			} else if r.pos+1 < len(r.data) && r.data[r.pos+1] == 0x00 {
+				r.acc = b
+				r.pos += 2
+			} else {
+				r.marker = true
+			}
+		}
+		r.n = 8
+	}
+
+	r.n--
+	return r.acc >> r.n & 1, nil
+}
+
+// receiveExtend reads s bits of a coefficient and converts them to a signed value as described in
+// section F.2.2.1 of the JPEG specification
+func (r *jpegBitReader) receiveExtend(s uint8) (int32, error) {
+	var v int32
+	for i := uint8(0); i < s; i++ {
+		bit, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | int32(bit)
+	}
+
+	if s > 0 && v < 1<<(s-1) {
+		v += -1<<s + 1
+	}
+
+	return v, nil
+}
+
+// restart skips remaining bits of the current byte and the following restart marker
+func (r *jpegBitReader) restart() error {
+	r.n = 0
+	r.marker = false
+
+	if r.pos+1 >= len(r.data) || r.data[r.pos] != 0xff || r.data[r.pos+1] < 0xd0 || r.data[r.pos+1] > 0xd7 {
+		return ErrJPEGFormat
+	}
+	r.pos += 2
+
+	return nil
+}
+
+// jpegBitWriter writes bits of entropy-coded segment stuffing zero byte after every 0xff byte
+type jpegBitWriter struct {
+	data []byte
+	acc  uint32
+	n    uint
+}
+
+func (w *jpegBitWriter) writeBits(bits uint32, n uint) {
+	w.acc = w.acc<<n | bits&(1<<n-1)
+	w.n += n
+
+	for w.n >= 8 {
+		b := byte(w.acc >> (w.n - 8))
+		w.data = append(w.data, b)
+		if b == 0xff {
+			w.data = append(w.data, 0x00)
+		}
+		w.n -= 8
+	}
+}
+
+// writeValue writes category of value v with encoder e followed by bits of the value
+func (w *jpegBitWriter) writeValue(e *jpegHuffmanEncoder, run uint8, v int32) {
+	a, bits := v, v
+	if a < 0 {
+		a = -a
+		bits--
+	}
+
+	var s uint8
+	for a > 0 {
+		s++
+		a >>= 1
+	}
+
+	e.encode(w, run<<4|s)
+	w.writeBits(uint32(bits), uint(s))
+}
+
+// flush pads the last byte with one bits
+func (w *jpegBitWriter) flush() {
+	if w.n > 0 {
+		w.writeBits(0xff, 8-w.n)
+	}
+}
@@ -0,0 +1,44 @@
+package imgio
+
+import (
+	"image"
+	"io"
+)
+
+// JPEGReadWriter stores one bit per usable DCT coefficient of JPEG in its least significant bit
+// like JSteg does. Coefficients equal to 0 and 1 are skipped, and the change of the lowest bit
+// never makes other coefficients equal to them, so the same coefficients are found on reading.
+// Generator gen has to iterate points of coefficients Bounds
+type JPEGReadWriter struct {
+	coeffs *JPEGCoefficients
+	*PointStorage
+}
+
+func NewJPEGReadWriter(coeffs *JPEGCoefficients, gen PointsSequenceGenerator) *JPEGReadWriter {
+	j := &JPEGReadWriter{
+		coeffs: coeffs,
+	}
+	j.PointStorage = NewPointStorage(j, gen)
+	return j
+}
+
+// Encode writes JPEG with embedded data to w
+func (j *JPEGReadWriter) Encode(w io.Writer) error {
+	j.mux.RLock()
+	defer j.mux.RUnlock()
+
+	return j.coeffs.Encode(w)
+}
+
+func (j *JPEGReadWriter) PointBits(_ image.Point) int {
+	return 1
+}
+
+func (j *JPEGReadWriter) ReadPointBits(p image.Point) uint64 {
+	return uint64(*j.coeffs.coefficient(p) & 1)
+}
+
+func (j *JPEGReadWriter) WritePointBits(p image.Point, value uint64) {
+	coef := j.coeffs.coefficient(p)
+	*coef = *coef&^1 | int32(value&1)
+}
@@ -0,0 +1,65 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_JPEGReadWriter_ImplementsInterfaces(t *testing.T) {
+	require.Implements(t, (*Storage)(nil), &JPEGReadWriter{})
+	require.Implements(t, (*io.ReaderAt)(nil), &JPEGReadWriter{})
+	require.Implements(t, (*io.WriterAt)(nil), &JPEGReadWriter{})
+}
+
+func Test_JPEGReadWriter_RoundTrip(t *testing.T) {
+	cover := newNoiseJPEG(image.NewRGBA(image.Rect(0, 0, 48, 40)), 85)
+
+	generators := map[string]func(rect image.Rectangle) PointsSequenceGenerator{
+		"Simple": func(rect image.Rectangle) PointsSequenceGenerator {
+			return NewSimplePointsSequenceGenerator(rect)
+		},
+		"Rand": func(rect image.Rectangle) PointsSequenceGenerator {
+			return NewRandPointsSequenceGenerator(rect, []byte("secret"))
+		},
+	}
+
+	for name, newGen := range generators {
+		coeffs, err := DecodeJPEGCoefficients(bytes.NewReader(cover))
+		require.Nil(t, err, name)
+
+		jrw := NewJPEGReadWriter(coeffs, newGen(coeffs.Bounds()))
+		data := randomBytes(t, jrw.Size())
+		n, err := jrw.Write(data)
+		require.Nil(t, err, name)
+		require.Equal(t, len(data), n, name)
+
+		encoded := bytes.NewBuffer(nil)
+		require.Nil(t, jrw.Encode(encoded), name)
+
+		// The result is valid JPEG
+		_, err = jpeg.Decode(bytes.NewReader(encoded.Bytes()))
+		require.Nil(t, err, name)
+
+		// Encode the image once more without changes
+		coeffs, err = DecodeJPEGCoefficients(encoded)
+		require.Nil(t, err, name)
+		encoded.Reset()
+		require.Nil(t, coeffs.Encode(encoded), name)
+
+		coeffs, err = DecodeJPEGCoefficients(encoded)
+		require.Nil(t, err, name)
+
+		jrw = NewJPEGReadWriter(coeffs, newGen(coeffs.Bounds()))
+		require.Equal(t, int64(len(data)), jrw.Size(), name)
+
+		actual, err := ioutil.ReadAll(jrw)
+		require.Nil(t, err, name)
+		require.Equal(t, data, actual, name)
+	}
+}
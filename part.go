@@ -0,0 +1,61 @@
+package imgio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// PartManifestSize is size of encoded part manifest in bytes: set ID, index and count of parts
+const PartManifestSize = 8 + 4 + 4
+
+var ErrPartManifest = errors.New("Invalid part manifest")
+
+// PartManifest describes a part of payload split across several images. Parts of one payload
+// share set ID and are joined in order of their indexes
+type PartManifest struct {
+	SetID uint64
+	Index uint32
+	Count uint32
+}
+
+func (m PartManifest) MarshalBinary() ([]byte, error) {
+	buff := make([]byte, PartManifestSize)
+	binary.BigEndian.PutUint64(buff, m.SetID)
+	binary.BigEndian.PutUint32(buff[8:], m.Index)
+	binary.BigEndian.PutUint32(buff[12:], m.Count)
+	return buff, nil
+}
+
+func (m *PartManifest) UnmarshalBinary(data []byte) error {
+	if len(data) < PartManifestSize {
+		return ErrPartManifest
+	}
+
+	index := binary.BigEndian.Uint32(data[8:])
+	count := binary.BigEndian.Uint32(data[12:])
+	if index >= count {
+		return ErrPartManifest
+	}
+
+	m.SetID = binary.BigEndian.Uint64(data)
+	m.Index = index
+	m.Count = count
+	return nil
+}
+
+// ReadPartManifest reads and decodes part manifest from r
+func ReadPartManifest(r io.Reader) (PartManifest, error) {
+	var manifest PartManifest
+
+	buff := make([]byte, PartManifestSize)
+	if _, err := io.ReadFull(r, buff); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return manifest, ErrPartManifest
+		}
+		return manifest, err
+	}
+
+	err := manifest.UnmarshalBinary(buff)
+	return manifest, err
+}
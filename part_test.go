@@ -0,0 +1,99 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_PartManifest_MarshalBinary(t *testing.T) {
+	manifest := PartManifest{
+		SetID: 0x0102030405060708,
+		Index: 2,
+		Count: 0x0a0b0c0d,
+	}
+
+	data, err := manifest.MarshalBinary()
+	require.Nil(t, err)
+	require.Equal(t, []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x00, 0x00, 0x00, 0x02,
+		0x0a, 0x0b, 0x0c, 0x0d,
+	}, data)
+
+	decoded, err := ReadPartManifest(bytes.NewReader(data))
+	require.Nil(t, err)
+	require.Equal(t, manifest, decoded)
+}
+
+func Test_PartManifest_UnmarshalBinary_Errors(t *testing.T) {
+	tests := []struct {
+		manifest    PartManifest
+		size        int
+		expectedErr error
+	}{
+		{PartManifest{Index: 0, Count: 1}, PartManifestSize, nil},
+		{PartManifest{Index: 4, Count: 5}, PartManifestSize, nil},
+		{PartManifest{Index: 0, Count: 1}, PartManifestSize - 1, ErrPartManifest},
+		{PartManifest{Index: 5, Count: 5}, PartManifestSize, ErrPartManifest},
+		{PartManifest{Index: 0, Count: 0}, PartManifestSize, ErrPartManifest},
+	}
+
+	for i, test := range tests {
+		data, _ := test.manifest.MarshalBinary()
+		_, err := ReadPartManifest(bytes.NewReader(data[:test.size]))
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+	}
+}
+
+func Test_PartManifest_Frames(t *testing.T) {
+	payload := randomBytes(t, 500)
+	const parts = 3
+
+	var storages []io.ReadWriteSeeker
+	chunk := (len(payload) + parts - 1) / parts
+
+	for i := 0; i < parts; i++ {
+		rect := image.Rect(0, 0, 14, 14)
+		storage := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+
+		fw, err := NewFrameWriter(storage, CodecSimplePoint32, 0, FrameFlagPart)
+		require.Nil(t, err, "Test index %d", i)
+
+		manifest, _ := PartManifest{SetID: 7, Index: uint32(i), Count: parts}.MarshalBinary()
+		end := (i + 1) * chunk
+		if end > len(payload) {
+			end = len(payload)
+		}
+		_, err = fw.Write(append(manifest, payload[i*chunk:end]...))
+		require.Nil(t, err, "Test index %d", i)
+		require.Nil(t, fw.Close(), "Test index %d", i)
+
+		storages = append(storages, storage)
+	}
+
+	readers := make([]io.Reader, parts)
+	for i := parts - 1; i >= 0; i-- {
+		_, err := storages[i].Seek(0, io.SeekStart)
+		require.Nil(t, err, "Test index %d", i)
+
+		fr, err := NewFrameReader(storages[i])
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, FrameFlagPart, fr.Header().Flags, "Test index %d", i)
+
+		manifest, err := ReadPartManifest(fr)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, uint64(7), manifest.SetID, "Test index %d", i)
+		require.Equal(t, uint32(parts), manifest.Count, "Test index %d", i)
+
+		readers[manifest.Index] = fr
+	}
+
+	actual, err := ioutil.ReadAll(io.MultiReader(readers...))
+	require.Nil(t, err)
+	require.Equal(t, payload, actual)
+}
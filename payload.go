@@ -0,0 +1,53 @@
+package imgio
+
+import "io"
+
+// payloadWriter closes stages of payload pipeline from the outermost to the innermost one
+type payloadWriter struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (w *payloadWriter) Close() error {
+	for _, closer := range w.closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewPayloadWriter returns writer which transforms payload according to frame flags before
+// writing to w: payload is compressed first and encrypted then. Close must be called to flush
+// all stages, it doesn't close w
+func NewPayloadWriter(w io.Writer, flags uint8, passphrase []byte) io.WriteCloser {
+	pw := &payloadWriter{Writer: w}
+
+	if flags&FrameFlagEncrypted != 0 {
+		enc := NewEncryptedWriter(pw.Writer, passphrase)
+		pw.Writer = enc
+		pw.closers = append([]io.Closer{enc}, pw.closers...)
+	}
+
+	if flags&FrameFlagCompressed != 0 {
+		cw := NewCompressedWriter(pw.Writer)
+		pw.Writer = cw
+		pw.closers = append([]io.Closer{cw}, pw.closers...)
+	}
+
+	return pw
+}
+
+// NewPayloadReader returns reader which restores payload read from r transformed according to
+// frame flags
+func NewPayloadReader(r io.Reader, flags uint8, passphrase []byte) io.Reader {
+	if flags&FrameFlagEncrypted != 0 {
+		r = NewEncryptedReader(r, passphrase)
+	}
+
+	if flags&FrameFlagCompressed != 0 {
+		r = NewCompressedReader(r)
+	}
+
+	return r
+}
@@ -0,0 +1,57 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_Payload_RoundTrip(t *testing.T) {
+	rect := image.Rect(0, 0, 15, 15)
+	payload := bytes.Repeat([]byte("compressible payload "), 100)
+
+	tests := []struct {
+		flags      uint8
+		passphrase []byte
+	}{
+		{0, nil},
+		{FrameFlagCompressed, nil},
+		{FrameFlagEncrypted, []byte("passphrase")},
+		{FrameFlagCompressed | FrameFlagEncrypted, []byte("passphrase")},
+	}
+
+	for i, test := range tests {
+		img := NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+
+		fw, err := NewFrameWriter(img, CodecSimplePoint32, 0, test.flags)
+		require.Nil(t, err, "Test index %d", i)
+
+		pw := NewPayloadWriter(fw, test.flags, test.passphrase)
+		_, err = pw.Write(payload)
+		if err == nil {
+			err = pw.Close()
+		}
+		if test.flags&FrameFlagCompressed == 0 {
+			// Uncompressed payload doesn't fit the image
+			require.Equal(t, ErrOverflow, err, "Test index %d", i)
+			continue
+		}
+		require.Nil(t, err, "Test index %d", i)
+		require.Nil(t, fw.Close(), "Test index %d", i)
+
+		_, err = img.Seek(0, io.SeekStart)
+		require.Nil(t, err, "Test index %d", i)
+
+		fr, err := NewFrameReader(img)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.flags, fr.Header().Flags, "Test index %d", i)
+
+		actual, err := ioutil.ReadAll(NewPayloadReader(fr, fr.Header().Flags, test.passphrase))
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, payload, actual, "Test index %d", i)
+	}
+}
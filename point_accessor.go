@@ -0,0 +1,301 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// DrawImageAccessor accesses points of any draw.Image through color.Color, colors written by
+// the read-writer are converted to the color model of the image
+type DrawImageAccessor struct {
+	img draw.Image
+	prw PointReadWriter
+}
+
+func NewDrawImageAccessor(img draw.Image, prw PointReadWriter) DrawImageAccessor {
+	return DrawImageAccessor{img, prw}
+}
+
+func (a DrawImageAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a DrawImageAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.At(p.X, p.Y), p)
+}
+
+func (a DrawImageAccessor) WritePointBits(p image.Point, value uint64) {
+	a.img.Set(p.X, p.Y, a.prw.WriteBits(value, a.img.At(p.X, p.Y), p))
+}
+
+// RGBAAccessor accesses points of image.RGBA reading and writing Pix directly
+type RGBAAccessor struct {
+	img *image.RGBA
+	prw PointReadWriterRGBA
+}
+
+func NewRGBAAccessor(img *image.RGBA, prw PointReadWriterRGBA) RGBAAccessor {
+	return RGBAAccessor{img, prw}
+}
+
+func (a RGBAAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a RGBAAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.rgbaAt(p.X, p.Y), p)
+}
+
+func (a RGBAAccessor) WritePointBits(p image.Point, value uint64) {
+	a.setRGBA(p.X, p.Y, a.prw.WriteBits(value, a.rgbaAt(p.X, p.Y), p))
+}
+
+// rgbaAt returns color of point (x, y) reading Pix directly
+func (a RGBAAccessor) rgbaAt(x, y int) color.RGBA {
+	pix := a.img.Pix[a.img.PixOffset(x, y):]
+	return color.RGBA{pix[0], pix[1], pix[2], pix[3]}
+}
+
+// setRGBA sets color c of point (x, y) writing Pix directly
+func (a RGBAAccessor) setRGBA(x, y int, c color.RGBA) {
+	pix := a.img.Pix[a.img.PixOffset(x, y):]
+	pix[0], pix[1], pix[2], pix[3] = c.R, c.G, c.B, c.A
+}
+
+// YCbCrAccessor accesses points of image.YCbCr with any chroma subsample ratio. Luma bits are
+// stored in every point, while chroma bits are stored only in the first point of the image which
+// shares a chroma sample, so each chroma sample is written once
+type YCbCrAccessor struct {
+	img *image.YCbCr
+	prw PointReadWriterYCbCr
+}
+
+func NewYCbCrAccessor(img *image.YCbCr, prw PointReadWriterYCbCr) YCbCrAccessor {
+	return YCbCrAccessor{img, prw}
+}
+
+func (a YCbCrAccessor) PointBits(p image.Point) int {
+	luma, chroma := ycbcrPlanesBits(a.prw, p)
+	if a.ownsChroma(p) {
+		return luma + chroma
+	}
+	return luma
+}
+
+func (a YCbCrAccessor) ReadPointBits(p image.Point) uint64 {
+	value := a.prw.ReadBits(a.img.YCbCrAt(p.X, p.Y), p)
+	if a.ownsChroma(p) {
+		return value
+	}
+
+	_, chroma := ycbcrPlanesBits(a.prw, p)
+	return value >> uint(chroma)
+}
+
+func (a YCbCrAccessor) WritePointBits(p image.Point, value uint64) {
+	src := a.img.YCbCrAt(p.X, p.Y)
+
+	if a.ownsChroma(p) {
+		c := a.prw.WriteBits(value, src, p)
+		a.img.Y[a.img.YOffset(p.X, p.Y)] = c.Y
+		a.img.Cb[a.img.COffset(p.X, p.Y)] = c.Cb
+		a.img.Cr[a.img.COffset(p.X, p.Y)] = c.Cr
+		return
+	}
+
+	// Chroma sample belongs to another point, keep its bits and write only luma
+	_, chroma := ycbcrPlanesBits(a.prw, p)
+	value = value<<uint(chroma) | a.prw.ReadBits(src, p)&bitsMask(chroma)
+	a.img.Y[a.img.YOffset(p.X, p.Y)] = a.prw.WriteBits(value, src, p).Y
+}
+
+// ownsChroma reports whether p is the first point of the image which shares its chroma sample
+func (a YCbCrAccessor) ownsChroma(p image.Point) bool {
+	h, v := subsampleFactors(a.img.SubsampleRatio)
+	rect := a.img.Rect
+	return (p.X == rect.Min.X || (p.X-1)/h != p.X/h) && (p.Y == rect.Min.Y || (p.Y-1)/v != p.Y/v)
+}
+
+// subsampleFactors returns numbers of points sharing a chroma sample horizontally and vertically
+func subsampleFactors(ratio image.YCbCrSubsampleRatio) (h, v int) {
+	switch ratio {
+	case image.YCbCrSubsampleRatio422:
+		return 2, 1
+	case image.YCbCrSubsampleRatio420:
+		return 2, 2
+	case image.YCbCrSubsampleRatio440:
+		return 1, 2
+	case image.YCbCrSubsampleRatio411:
+		return 4, 1
+	case image.YCbCrSubsampleRatio410:
+		return 4, 2
+	}
+	return 1, 1
+}
+
+// NRGBAAccessor accesses points of image.NRGBA. The read-writer gets color.NRGBA, colors written
+// by it are stored as is if they are color.NRGBA and converted otherwise
+type NRGBAAccessor struct {
+	img *image.NRGBA
+	prw PointReadWriter
+}
+
+func NewNRGBAAccessor(img *image.NRGBA, prw PointReadWriter) NRGBAAccessor {
+	return NRGBAAccessor{img, prw}
+}
+
+func (a NRGBAAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a NRGBAAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.NRGBAAt(p.X, p.Y), p)
+}
+
+func (a NRGBAAccessor) WritePointBits(p image.Point, value uint64) {
+	c := a.prw.WriteBits(value, a.img.NRGBAAt(p.X, p.Y), p)
+	a.img.SetNRGBA(p.X, p.Y, color.NRGBAModel.Convert(c).(color.NRGBA))
+}
+
+// NRGBA64Accessor accesses points of image.NRGBA64 like NRGBAAccessor does
+type NRGBA64Accessor struct {
+	img *image.NRGBA64
+	prw PointReadWriter
+}
+
+func NewNRGBA64Accessor(img *image.NRGBA64, prw PointReadWriter) NRGBA64Accessor {
+	return NRGBA64Accessor{img, prw}
+}
+
+func (a NRGBA64Accessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a NRGBA64Accessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.NRGBA64At(p.X, p.Y), p)
+}
+
+func (a NRGBA64Accessor) WritePointBits(p image.Point, value uint64) {
+	c := a.prw.WriteBits(value, a.img.NRGBA64At(p.X, p.Y), p)
+	a.img.SetNRGBA64(p.X, p.Y, color.NRGBA64Model.Convert(c).(color.NRGBA64))
+}
+
+// GrayAccessor accesses points of image.Gray. The read-writer gets color.Gray, colors written by
+// it are stored as is if they are color.Gray and converted otherwise
+type GrayAccessor struct {
+	img *image.Gray
+	prw PointReadWriter
+}
+
+func NewGrayAccessor(img *image.Gray, prw PointReadWriter) GrayAccessor {
+	return GrayAccessor{img, prw}
+}
+
+func (a GrayAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a GrayAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.GrayAt(p.X, p.Y), p)
+}
+
+func (a GrayAccessor) WritePointBits(p image.Point, value uint64) {
+	c := a.prw.WriteBits(value, a.img.GrayAt(p.X, p.Y), p)
+	a.img.SetGray(p.X, p.Y, color.GrayModel.Convert(c).(color.Gray))
+}
+
+// Gray16Accessor accesses points of image.Gray16 like GrayAccessor does
+type Gray16Accessor struct {
+	img *image.Gray16
+	prw PointReadWriter
+}
+
+func NewGray16Accessor(img *image.Gray16, prw PointReadWriter) Gray16Accessor {
+	return Gray16Accessor{img, prw}
+}
+
+func (a Gray16Accessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a Gray16Accessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.Gray16At(p.X, p.Y), p)
+}
+
+func (a Gray16Accessor) WritePointBits(p image.Point, value uint64) {
+	c := a.prw.WriteBits(value, a.img.Gray16At(p.X, p.Y), p)
+	a.img.SetGray16(p.X, p.Y, color.Gray16Model.Convert(c).(color.Gray16))
+}
+
+// CMYKAccessor accesses points of image.CMYK like GrayAccessor does, so C, M, Y and K channels
+// are read and written without conversion to RGB
+type CMYKAccessor struct {
+	img *image.CMYK
+	prw PointReadWriter
+}
+
+func NewCMYKAccessor(img *image.CMYK, prw PointReadWriter) CMYKAccessor {
+	return CMYKAccessor{img, prw}
+}
+
+func (a CMYKAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a CMYKAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.CMYKAt(p.X, p.Y), p)
+}
+
+func (a CMYKAccessor) WritePointBits(p image.Point, value uint64) {
+	c := a.prw.WriteBits(value, a.img.CMYKAt(p.X, p.Y), p)
+	a.img.SetCMYK(p.X, p.Y, color.CMYKModel.Convert(c).(color.CMYK))
+}
+
+// NYCbCrAAccessor accesses points of image.NYCbCrA writing luma and alpha samples of a point.
+// Chroma samples are never written, so any subsample ratio of the image is kept and read-writer
+// must change only luma and alpha like PointReadWriterNYCbCrABits does
+type NYCbCrAAccessor struct {
+	img *image.NYCbCrA
+	prw PointReadWriter
+}
+
+func NewNYCbCrAAccessor(img *image.NYCbCrA, prw PointReadWriter) NYCbCrAAccessor {
+	return NYCbCrAAccessor{img, prw}
+}
+
+func (a NYCbCrAAccessor) PointBits(p image.Point) int {
+	return a.prw.Bits(p)
+}
+
+func (a NYCbCrAAccessor) ReadPointBits(p image.Point) uint64 {
+	return a.prw.ReadBits(a.img.NYCbCrAAt(p.X, p.Y), p)
+}
+
+func (a NYCbCrAAccessor) WritePointBits(p image.Point, value uint64) {
+	c := color.NYCbCrAModel.Convert(a.prw.WriteBits(value, a.img.NYCbCrAAt(p.X, p.Y), p)).(color.NYCbCrA)
+	a.img.Y[a.img.YOffset(p.X, p.Y)] = c.Y
+	a.img.A[a.img.AOffset(p.X, p.Y)] = c.A
+}
+
+// NewImageAccessor returns the fastest accessor of points of img with read-writer prw. Images which
+// aren't draw.Image have to be image.NYCbCrA, nil is returned for others
+func NewImageAccessor(img image.Image, prw PointReadWriter) PointAccessor {
+	switch img := img.(type) {
+	case *image.NRGBA:
+		return NewNRGBAAccessor(img, prw)
+	case *image.NRGBA64:
+		return NewNRGBA64Accessor(img, prw)
+	case *image.Gray:
+		return NewGrayAccessor(img, prw)
+	case *image.Gray16:
+		return NewGray16Accessor(img, prw)
+	case *image.CMYK:
+		return NewCMYKAccessor(img, prw)
+	case *image.NYCbCrA:
+		return NewNYCbCrAAccessor(img, prw)
+	case draw.Image:
+		return NewDrawImageAccessor(img, prw)
+	}
+	return nil
+}
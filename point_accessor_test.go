@@ -0,0 +1,109 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+// grayTestReadWriter stores bits in luminance of gray colors
+type grayTestReadWriter struct {
+	bits int
+}
+
+func (prw grayTestReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	return uint64(color.Gray16Model.Convert(c).(color.Gray16).Y) >> uint(16-prw.bits)
+}
+
+func (prw grayTestReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	if prw.bits == 8 {
+		return color.Gray{uint8(value)}
+	}
+	return color.Gray16{uint16(value)}
+}
+
+func (prw grayTestReadWriter) Bits(_ image.Point) int {
+	return prw.bits
+}
+
+func Test_NewImageAccessor(t *testing.T) {
+	rect := image.Rect(0, 0, 2, 2)
+	tests := []struct {
+		img      image.Image
+		expected PointAccessor
+	}{
+		{image.NewRGBA(rect), DrawImageAccessor{}},
+		{image.NewNRGBA(rect), NRGBAAccessor{}},
+		{image.NewNRGBA64(rect), NRGBA64Accessor{}},
+		{image.NewGray(rect), GrayAccessor{}},
+		{image.NewGray16(rect), Gray16Accessor{}},
+		{image.NewCMYK(rect), CMYKAccessor{}},
+		{image.NewNYCbCrA(rect, image.YCbCrSubsampleRatio420), NYCbCrAAccessor{}},
+	}
+
+	for i, test := range tests {
+		require.IsType(t, test.expected, NewImageAccessor(test.img, SmartPoint8ReadWriter{}), "Test index %d", i)
+	}
+
+	require.Nil(t, NewImageAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio420), SmartPoint8ReadWriter{}))
+}
+
+func Test_PointAccessors_RoundTrip(t *testing.T) {
+	rect := image.Rect(0, 0, 6, 5)
+	opaque := func(img draw.Image) draw.Image {
+		draw.Draw(img, rect, image.Opaque, image.ZP, draw.Src)
+		return img
+	}
+	cmykBits, err := NewPointReadWriterCMYKBits(8)
+	require.Nil(t, err)
+	nycbcraBits, err := NewPointReadWriterNYCbCrABits(3)
+	require.Nil(t, err)
+
+	accessors := []PointAccessor{
+		NewDrawImageAccessor(image.NewRGBA64(rect), SimplePoint64ReadWriter{}),
+		NewRGBAAccessor(image.NewRGBA(rect), PointReadWriterRGBASimple{}),
+		NewYCbCrAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio444), PointReadWriterYCbCrSimple{}),
+		NewNRGBAAccessor(opaque(image.NewNRGBA(rect)).(*image.NRGBA), SmartPoint8ReadWriter{}),
+		NewNRGBA64Accessor(opaque(image.NewNRGBA64(rect)).(*image.NRGBA64), SmartPoint8ReadWriter{}),
+		NewGrayAccessor(image.NewGray(rect), grayTestReadWriter{8}),
+		NewGray16Accessor(image.NewGray16(rect), grayTestReadWriter{16}),
+		NewCMYKAccessor(image.NewCMYK(rect), cmykBits),
+		NewNYCbCrAAccessor(image.NewNYCbCrA(rect, image.YCbCrSubsampleRatio420), nycbcraBits),
+	}
+
+	for i, acc := range accessors {
+		storage := NewPointStorage(acc, NewSimplePointsSequenceGenerator(rect))
+		data := randomBytes(t, storage.Size())
+
+		n, err := storage.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, len(data), n, "Test index %d", i)
+
+		actual := make([]byte, len(data))
+		n, err = storage.ReadAt(actual, 0)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+	}
+}
+
+func Test_NYCbCrAAccessor_KeepsChroma(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 4)
+	img := image.NewNYCbCrA(rect, image.YCbCrSubsampleRatio420)
+	for i := range img.Cb {
+		img.Cb[i], img.Cr[i] = byte(i), byte(0xff-i)
+	}
+	cb, cr := append([]byte{}, img.Cb...), append([]byte{}, img.Cr...)
+
+	prw, err := NewPointReadWriterNYCbCrABits(8)
+	require.Nil(t, err)
+	storage := NewPointStorage(NewNYCbCrAAccessor(img, prw), NewSimplePointsSequenceGenerator(rect))
+	require.EqualValues(t, rect.Dx()*rect.Dy()*2, storage.Size())
+
+	_, err = storage.Write(randomBytes(t, storage.Size()))
+	require.Nil(t, err)
+	require.Equal(t, cb, img.Cb)
+	require.Equal(t, cr, img.Cr)
+}
@@ -6,218 +6,136 @@ import (
 )
 
 type PointReadWriter interface {
-	// Read reads bytes from color c from position start on point p
-	Read(start int, c color.Color, p image.Point) ([]byte, int)
-	// Write writes bytes b into color c starts on position start on point p and returns number of written bytes
-	Write(b []byte, start int, c color.Color, p image.Point) (color.Color, int)
-	// Return number of bytes possible to be written to point p on current image
-	Size(p image.Point) int64
+	// ReadBits returns bits stored in color c on point p in the lowest Bits(p) bits
+	ReadBits(c color.Color, p image.Point) uint64
+	// WriteBits writes the lowest Bits(p) bits of value into color src on point p and returns new color
+	WriteBits(value uint64, src color.Color, p image.Point) color.Color
+	// Return number of bits possible to be written to point p on current image
+	Bits(p image.Point) int
 }
 
 const SimplePoint32Capacity = 4
 
 type SimplePoint32ReadWriter struct{}
 
-func (SimplePoint32ReadWriter) Read(start int, c color.Color, p image.Point) ([]byte, int) {
-	if start >= SimplePoint32Capacity {
-		return []byte{}, 0
-	}
-
-	buff := make([]byte, SimplePoint32Capacity-start)
+func (SimplePoint32ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
 	r, g, b, a := c.RGBA()
-	data := []uint32{r, g, b, a}
-
-	n := 0
-	for i, v := range data[start:] {
-		buff[i] = byte(v)
-		n++
-	}
-
-	return buff, n
+	return uint64(byte(r))<<24 | uint64(byte(g))<<16 | uint64(byte(b))<<8 | uint64(byte(a))
 }
 
-func (SimplePoint32ReadWriter) Write(b []byte, start int, src color.Color, p image.Point) (color.Color, int) {
-	srcR, srcG, srcB, srcA := src.RGBA()
-	c := &color.RGBA{byte(srcR), byte(srcG), byte(srcB), byte(srcA)}
-
-	if start >= SimplePoint32Capacity {
-		return c, 0
-	}
-
-	addrs := []*uint8{&c.R, &c.G, &c.B, &c.A}
-
-	n := 0
-	for i, addr := range addrs[start:] {
-		if i >= len(b) {
-			break
-		}
-		*addr = b[i]
-		n++
-	}
-
-	return c, n
+func (SimplePoint32ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	return &color.RGBA{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
 }
 
-func (SimplePoint32ReadWriter) Size(_ image.Point) int64 {
-	return SimplePoint32Capacity
+func (SimplePoint32ReadWriter) Bits(_ image.Point) int {
+	return SimplePoint32Capacity * 8
 }
 
 const SimplePoint64Capacity = 8
 
 type SimplePoint64ReadWriter struct{}
 
-func (SimplePoint64ReadWriter) Read(start int, c color.Color, p image.Point) ([]byte, int) {
-	if start >= SimplePoint64Capacity {
-		return []byte{}, 0
-	}
-
-	buff := make([]byte, SimplePoint64Capacity-start)
+func (SimplePoint64ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
 	r, g, b, a := c.RGBA()
-	data := []uint32{r, g, b, a}
-	n := 0
-	skip := start % 2
-	for _, v := range data[start/2:] {
-		if skip == 1 {
-			skip = 0
-		} else {
-			buff[n] = byte(v >> 8)
-			n++
-		}
-		buff[n] = byte(v)
-		n++
-	}
+	return uint64(uint16(r))<<48 | uint64(uint16(g))<<32 | uint64(uint16(b))<<16 | uint64(uint16(a))
+}
 
-	return buff, n
+func (SimplePoint64ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	return &color.RGBA64{uint16(value >> 48), uint16(value >> 32), uint16(value >> 16), uint16(value)}
 }
 
-func (SimplePoint64ReadWriter) Write(b []byte, start int, src color.Color, p image.Point) (color.Color, int) {
-	srcR, srcG, srcB, srcA := src.RGBA()
-	c := &color.RGBA64{uint16(srcR), uint16(srcG), uint16(srcB), uint16(srcA)}
+func (SimplePoint64ReadWriter) Bits(_ image.Point) int {
+	return SimplePoint64Capacity * 8
+}
 
-	if start >= SimplePoint64Capacity {
-		return c, 0
-	}
+// SmartPoint8ReadWriter stores one byte per point in the lowest bits of color channels: 3 bits
+// in R, 3 bits in G and 2 bits in B. Alpha is deliberately left intact because changing it in
+// premultiplied colors would corrupt the other channels. Bits are stored in the highest byte of
+// channels of colors with 16-bit channels, and non-premultiplied colors are changed without
+// premultiplying, so the colors stay visually unchanged. Carrier must keep R, G and B channels
+// apart: image.RGBA, image.RGBA64, image.NRGBA or image.NRGBA64. Grayscale, CMYK and paletted
+// carriers convert written colors and lose the payload
+type SmartPoint8ReadWriter struct{}
 
-	addrs := []*uint16{&c.R, &c.G, &c.B, &c.A}
-	n := 0
-	i := 0
-	skip := start % 2
-	for _, addr := range addrs[start/2:] {
-		if i >= len(b) {
-			break
-		}
-		if skip == 1 {
-			skip = 0
-			i++
-
-			*addr &= 0xff00
-			*addr |= uint16(b[i])
-			n++
-			i++
-		} else {
-			*addr &= 0x00ff
-			*addr |= uint16(b[i]) << 8
-			n++
-			i++
-
-			if i >= len(b) {
-				return c, n
-			}
-
-			*addr &= 0xff00
-			*addr |= uint16(b[i])
-			n++
-			i++
-		}
-	}
+const SmartPoint8Capacity = 1
 
-	return c, n
-}
+func (SmartPoint8ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	var r, g, b byte
+
+	switch c := c.(type) {
+	case color.NRGBA:
+		r, g, b = c.R, c.G, c.B
+	case color.NRGBA64:
+		r, g, b = byte(c.R>>8), byte(c.G>>8), byte(c.B>>8)
+	case color.RGBA64:
+		r, g, b = byte(c.R>>8), byte(c.G>>8), byte(c.B>>8)
+	default:
+		rgba := color.RGBAModel.Convert(c).(color.RGBA)
+		r, g, b = rgba.R, rgba.G, rgba.B
+	}
 
-func (SimplePoint64ReadWriter) Size(_ image.Point) int64 {
-	return SimplePoint64Capacity
+	return uint64(r&0x07<<5 | g&0x07<<2 | b&0x03)
 }
 
-type SmartPoint8ReadWriter struct{}
+func (SmartPoint8ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	switch src := src.(type) {
+	case color.NRGBA:
+		src.R, src.G, src.B = smartPoint8Channels(byte(value), src.R, src.G, src.B)
+		return src
+	case color.NRGBA64:
+		src.R, src.G, src.B = smartPoint8Channels16(byte(value), src.R, src.G, src.B)
+		return src
+	case color.RGBA64:
+		src.R, src.G, src.B = smartPoint8Channels16(byte(value), src.R, src.G, src.B)
+		return src
+	}
 
-const SmartPoint8Capacity = 1
+	c := color.RGBAModel.Convert(src).(color.RGBA)
+	c.R, c.G, c.B = smartPoint8Channels(byte(value), c.R, c.G, c.B)
+	return &c
+}
 
-func (SmartPoint8ReadWriter) Read(start int, c color.Color, p image.Point) ([]byte, int) {
-	// TODO: Implement method.
-	return []byte{}, 0
+func (SmartPoint8ReadWriter) Bits(_ image.Point) int {
+	return SmartPoint8Capacity * 8
 }
 
-func (SmartPoint8ReadWriter) Write(b []byte, start int, src color.Color, p image.Point) (color.Color, int) {
-	// TODO: Implement method.
-	return nil, 0
+// smartPoint8Channels returns 8-bit channels r, g and b with value stored in their lowest bits
+func smartPoint8Channels(value, r, g, b byte) (byte, byte, byte) {
+	return r&0xf8 | value&0xe0>>5, g&0xf8 | value&0x1c>>2, b&0xfc | value&0x03
 }
 
-func (SmartPoint8ReadWriter) Size(_ image.Point) int64 {
-	return SmartPoint8Capacity
+// smartPoint8Channels16 returns 16-bit channels r, g and b with value stored in the lowest bits of
+// their highest bytes
+func smartPoint8Channels16(value byte, r, g, b uint16) (uint16, uint16, uint16) {
+	r8, g8, b8 := smartPoint8Channels(value, byte(r>>8), byte(g>>8), byte(b>>8))
+	return uint16(r8)<<8 | r&0xff, uint16(g8)<<8 | g&0xff, uint16(b8)<<8 | b&0xff
 }
 
 type GentlePoint16ReadWriter struct{}
 
 const GentlePoint16Capacity = 2
 
-func (GentlePoint16ReadWriter) Read(start int, c color.Color, p image.Point) ([]byte, int) {
-	if start >= GentlePoint16Capacity {
-		return []byte{}, 0
-	}
-
-	buff := make([]byte, GentlePoint16Capacity-start)
+func (GentlePoint16ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
 	r, g, b, a := c.RGBA()
-	n := 0
-
-	if start == 0 {
-		buff[n] = byte(r) & 0x0f << 4
-		buff[n] |= byte(g) & 0x0f
-		n++
-	}
-
-	buff[n] = byte(b) & 0x0f << 4
-	buff[n] |= byte(a) & 0x0f
-	n++
-
-	return buff, n
+	return uint64(r&0x0f)<<12 | uint64(g&0x0f)<<8 | uint64(b&0x0f)<<4 | uint64(a&0x0f)
 }
 
-func (GentlePoint16ReadWriter) Write(b []byte, start int, src color.Color, p image.Point) (color.Color, int) {
+func (GentlePoint16ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
 	srcR, srcG, srcB, srcA := src.RGBA()
 	c := &color.RGBA{uint8(srcR), uint8(srcG), uint8(srcB), uint8(srcA)}
 
-	if len(b) == 0 {
-		return c, 0
-	}
-
-	if start >= GentlePoint16Capacity {
-		return c, 0
-	}
-
-	i := 0
-
-	if start == 0 {
-		c.R &= 0xf0
-		c.R |= b[i] & 0xf0 >> 4
-		c.G &= 0xf0
-		c.G |= b[i] & 0x0f
-		i++
-		c.B &= 0xf0
-		c.B |= b[i] & 0xf0 >> 4
-		c.A &= 0xf0
-		c.A |= b[i] & 0x0f
-		i++
-	} else if start == 1 {
-		c.B &= 0xf0
-		c.B |= b[i] & 0xf0 >> 4
-		c.A &= 0xf0
-		c.A |= b[i] & 0x0f
-		i++
-	}
+	c.R &= 0xf0
+	c.R |= byte(value>>12) & 0x0f
+	c.G &= 0xf0
+	c.G |= byte(value>>8) & 0x0f
+	c.B &= 0xf0
+	c.B |= byte(value>>4) & 0x0f
+	c.A &= 0xf0
+	c.A |= byte(value) & 0x0f
 
-	return c, i
+	return c
 }
 
-func (GentlePoint16ReadWriter) Size(_ image.Point) int64 {
-	return GentlePoint16Capacity
+func (GentlePoint16ReadWriter) Bits(_ image.Point) int {
+	return GentlePoint16Capacity * 8
 }
@@ -0,0 +1,54 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+)
+
+// PointReadWriterCMYKBits stores bits in the lowest bits of C, M, Y and K channels of a point.
+// Colors written by it are color.CMYK, so carrier must be image.CMYK
+type PointReadWriterCMYKBits struct {
+	bits int
+}
+
+// PointReadWriterCMYKBitsMax is the greatest number of bits per channel of CMYK codec
+const PointReadWriterCMYKBitsMax = 8
+
+func NewPointReadWriterCMYKBits(bits int) (PointReadWriterCMYKBits, error) {
+	if bits < ChannelBitsMin || bits > PointReadWriterCMYKBitsMax {
+		return PointReadWriterCMYKBits{}, ErrChannelBits
+	}
+
+	return PointReadWriterCMYKBits{
+		bits: bits,
+	}, nil
+}
+
+func (prw PointReadWriterCMYKBits) ReadBits(c color.Color, p image.Point) uint64 {
+	cmyk := color.CMYKModel.Convert(c).(color.CMYK)
+	mask := bitsMask(prw.bits)
+	var value uint64
+
+	for _, v := range []uint8{cmyk.C, cmyk.M, cmyk.Y, cmyk.K} {
+		value = value<<uint(prw.bits) | uint64(v)&mask
+	}
+
+	return value
+}
+
+func (prw PointReadWriterCMYKBits) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	cmyk := color.CMYKModel.Convert(src).(color.CMYK)
+	mask := bitsMask(prw.bits)
+	data := []*uint8{&cmyk.C, &cmyk.M, &cmyk.Y, &cmyk.K}
+
+	for i := len(data) - 1; i >= 0; i-- {
+		*data[i] = *data[i]&^uint8(mask) | uint8(value&mask)
+		value >>= uint(prw.bits)
+	}
+
+	return cmyk
+}
+
+func (prw PointReadWriterCMYKBits) Bits(_ image.Point) int {
+	return 4 * prw.bits
+}
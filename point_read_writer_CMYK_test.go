@@ -0,0 +1,82 @@
+package imgio
+
+import (
+	"crypto/rand"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_NewPointReadWriterCMYKBits(t *testing.T) {
+	tests := []struct {
+		bits int
+
+		expectedErr error
+	}{
+		{0, ErrChannelBits},
+		{1, nil},
+		{8, nil},
+		{9, ErrChannelBits},
+	}
+
+	for i, test := range tests {
+		prw, err := NewPointReadWriterCMYKBits(test.bits)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		if err == nil {
+			require.Equal(t, 4*test.bits, prw.Bits(image.Point{}), "Test index %d", i)
+		}
+	}
+}
+
+func Test_PointReadWriterCMYKBits_ReadWriteBits(t *testing.T) {
+	tests := []struct {
+		bits  int
+		value uint64
+		src   color.CMYK
+
+		expectedColor color.CMYK
+	}{
+		{1, 0xa, color.CMYK{0x10, 0x11, 0x12, 0x13}, color.CMYK{0x11, 0x10, 0x13, 0x12}},
+		{2, 0xe4, color.CMYK{0xff, 0xff, 0xff, 0xff}, color.CMYK{0xff, 0xfe, 0xfd, 0xfc}},
+		{8, 0x01020304, color.CMYK{0xff, 0, 0xff, 0}, color.CMYK{1, 2, 3, 4}},
+	}
+
+	for i, test := range tests {
+		prw, err := NewPointReadWriterCMYKBits(test.bits)
+		require.Nil(t, err, "Test index %d", i)
+		c := prw.WriteBits(test.value, test.src, image.Point{})
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+		require.Equal(t, test.value, prw.ReadBits(c, image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_PointReadWriterCMYKBits_Image_KeepsCover(t *testing.T) {
+	rect := image.Rect(0, 0, 7, 5)
+	prw, err := NewPointReadWriterCMYKBits(2)
+	require.Nil(t, err)
+
+	cover := image.NewCMYK(rect)
+	_, err = rand.Read(cover.Pix)
+	require.Nil(t, err)
+	carrier := image.NewCMYK(rect)
+	copy(carrier.Pix, cover.Pix)
+
+	img := NewImage(carrier, NewSimplePointsSequenceGenerator(rect), prw)
+	data := make([]byte, img.Size())
+	_, err = rand.Read(data)
+	require.Nil(t, err)
+	_, err = img.Write(data)
+	require.Nil(t, err)
+
+	for i := range cover.Pix {
+		require.Equal(t, cover.Pix[i]&^0x3, carrier.Pix[i]&^0x3, "Sample index %d", i)
+	}
+
+	actual := make([]byte, len(data))
+	_, err = io.ReadFull(NewImage(carrier, NewSimplePointsSequenceGenerator(rect), prw), actual)
+	require.Nil(t, err)
+	require.Equal(t, data, actual)
+}
@@ -0,0 +1,73 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+)
+
+// GrayLSBReadWriter stores a bit in the lowest bit of luminance of a point. Colors written by it
+// are color.Gray, or color.Gray16 for color.Gray16 points of image.Gray16, so carrier must be
+// image.Gray or image.Gray16 to stay grayscale
+type GrayLSBReadWriter struct{}
+
+func (GrayLSBReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	if gray, ok := c.(color.Gray16); ok {
+		return uint64(gray.Y & 1)
+	}
+	return uint64(color.GrayModel.Convert(c).(color.Gray).Y & 1)
+}
+
+func (GrayLSBReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	if gray, ok := src.(color.Gray16); ok {
+		return color.Gray16{Y: gray.Y&^1 | uint16(value&1)}
+	}
+	gray := color.GrayModel.Convert(src).(color.Gray)
+	return color.Gray{Y: gray.Y&^1 | byte(value&1)}
+}
+
+func (GrayLSBReadWriter) Bits(_ image.Point) int {
+	return 1
+}
+
+// GrayBitsReadWriter stores bits in the lowest bits of luminance of a point. If number of bits is
+// not greater than 8 and the point isn't color.Gray16 bits are stored in 8-bit luminance as
+// color.Gray, otherwise in 16-bit luminance as color.Gray16, so image.Gray16 must be used
+type GrayBitsReadWriter struct {
+	bits int
+}
+
+func NewGrayBitsReadWriter(bits int) (GrayBitsReadWriter, error) {
+	if bits < ChannelBitsMin || bits > ChannelBitsMax {
+		return GrayBitsReadWriter{}, ErrChannelBits
+	}
+
+	return GrayBitsReadWriter{
+		bits: bits,
+	}, nil
+}
+
+func (prw GrayBitsReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	mask := bitsMask(prw.bits)
+
+	if _, ok := c.(color.Gray16); prw.bits <= 8 && !ok {
+		return uint64(color.GrayModel.Convert(c).(color.Gray).Y) & mask
+	}
+
+	return uint64(color.Gray16Model.Convert(c).(color.Gray16).Y) & mask
+}
+
+func (prw GrayBitsReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	mask := bitsMask(prw.bits)
+
+	if _, ok := src.(color.Gray16); prw.bits <= 8 && !ok {
+		gray := color.GrayModel.Convert(src).(color.Gray)
+		return color.Gray{Y: gray.Y&^byte(mask) | byte(value&mask)}
+	}
+
+	gray := color.Gray16Model.Convert(src).(color.Gray16)
+	return color.Gray16{Y: gray.Y&^uint16(mask) | uint16(value&mask)}
+}
+
+func (prw GrayBitsReadWriter) Bits(_ image.Point) int {
+	return prw.bits
+}
@@ -0,0 +1,170 @@
+package imgio
+
+import (
+	"bytes"
+	"crypto/rand"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_GrayLSBReadWriter_ReadWriteBits(t *testing.T) {
+	tests := []struct {
+		value uint64
+		src   color.Color
+
+		expectedColor color.Color
+	}{
+		{1, color.Gray{0x80}, color.Gray{0x81}},
+		{0, color.Gray{0x81}, color.Gray{0x80}},
+		{1, color.Gray{0xff}, color.Gray{0xff}},
+		{0, color.Gray16{0xffff}, color.Gray16{0xfffe}},
+		{1, color.Gray16{0x393a}, color.Gray16{0x393b}},
+		{1, color.RGBA{0x10, 0x10, 0x10, 0xff}, color.Gray{0x11}},
+	}
+
+	for i, test := range tests {
+		c := GrayLSBReadWriter{}.WriteBits(test.value, test.src, image.Point{})
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+		require.Equal(t, test.value, GrayLSBReadWriter{}.ReadBits(c, image.Point{}), "Test index %d", i)
+	}
+
+	require.Equal(t, 1, GrayLSBReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_NewGrayBitsReadWriter(t *testing.T) {
+	tests := []struct {
+		bits int
+
+		expectedErr error
+	}{
+		{0, ErrChannelBits},
+		{1, nil},
+		{8, nil},
+		{16, nil},
+		{17, ErrChannelBits},
+	}
+
+	for i, test := range tests {
+		prw, err := NewGrayBitsReadWriter(test.bits)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		if err == nil {
+			require.Equal(t, test.bits, prw.Bits(image.Point{}), "Test index %d", i)
+		}
+	}
+}
+
+func Test_GrayBitsReadWriter_ReadWriteBits(t *testing.T) {
+	tests := []struct {
+		bits  int
+		value uint64
+		src   color.Color
+
+		expectedColor color.Color
+	}{
+		{2, 3, color.Gray{0x80}, color.Gray{0x83}},
+		{4, 0x5, color.Gray{0xff}, color.Gray{0xf5}},
+		{8, 0xab, color.Gray{0x12}, color.Gray{0xab}},
+		{3, 0x5, color.Gray16{0x1234}, color.Gray16{0x1235}},
+		{12, 0xabc, color.Gray16{0x1234}, color.Gray16{0x1abc}},
+		{16, 0xabcd, color.Gray{0x12}, color.Gray16{0xabcd}},
+	}
+
+	for i, test := range tests {
+		prw, err := NewGrayBitsReadWriter(test.bits)
+		require.Nil(t, err, "Test index %d", i)
+		c := prw.WriteBits(test.value, test.src, image.Point{})
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+		require.Equal(t, test.value, prw.ReadBits(c, image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_GrayReadWriters_Image_PNG(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 9)
+	grayBits3, err := NewGrayBitsReadWriter(3)
+	require.Nil(t, err)
+	grayBits11, err := NewGrayBitsReadWriter(11)
+	require.Nil(t, err)
+
+	tests := []struct {
+		img draw.Image
+		prw PointReadWriter
+	}{
+		{image.NewGray(rect), GrayLSBReadWriter{}},
+		{image.NewGray(rect), grayBits3},
+		{image.NewGray16(rect), grayBits11},
+	}
+
+	for i, test := range tests {
+		img := NewImage(test.img, NewSimplePointsSequenceGenerator(rect), test.prw)
+		data := make([]byte, img.Size())
+		_, err := rand.Read(data)
+		require.Nil(t, err, "Test index %d", i)
+		_, err = img.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		buff := bytes.NewBuffer(nil)
+		require.Nil(t, png.Encode(buff, test.img), "Test index %d", i)
+		decoded, err := png.Decode(buff)
+		require.Nil(t, err, "Test index %d", i)
+		require.IsType(t, test.img, decoded, "Test index %d", i)
+
+		actual := make([]byte, len(data))
+		_, err = io.ReadFull(NewImage(decoded.(draw.Image), NewSimplePointsSequenceGenerator(rect), test.prw), actual)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+	}
+}
+
+func Test_GrayReadWriters_Gray16_KeepsCover(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 9)
+	grayBits3, err := NewGrayBitsReadWriter(3)
+	require.Nil(t, err)
+
+	tests := []struct {
+		prw  PointReadWriter
+		mask uint16
+	}{
+		{GrayLSBReadWriter{}, 0x1},
+		{grayBits3, 0x7},
+	}
+
+	for i, test := range tests {
+		cover := image.NewGray16(rect)
+		_, err := rand.Read(cover.Pix)
+		require.Nil(t, err, "Test index %d", i)
+		carrier := image.NewGray16(rect)
+		copy(carrier.Pix, cover.Pix)
+
+		img := NewImage(carrier, NewSimplePointsSequenceGenerator(rect), test.prw)
+		data := make([]byte, img.Size())
+		_, err = rand.Read(data)
+		require.Nil(t, err, "Test index %d", i)
+		_, err = img.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		buff := bytes.NewBuffer(nil)
+		require.Nil(t, png.Encode(buff, carrier), "Test index %d", i)
+		decoded, err := png.Decode(buff)
+		require.Nil(t, err, "Test index %d", i)
+		require.IsType(t, carrier, decoded, "Test index %d", i)
+
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				expected := cover.Gray16At(x, y).Y &^ test.mask
+				actual := decoded.(*image.Gray16).Gray16At(x, y).Y &^ test.mask
+				require.Equal(t, expected, actual, "Test index %d", i)
+			}
+		}
+
+		actualData := make([]byte, len(data))
+		_, err = io.ReadFull(NewImage(decoded.(draw.Image), NewSimplePointsSequenceGenerator(rect), test.prw), actualData)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actualData, "Test index %d", i)
+	}
+}
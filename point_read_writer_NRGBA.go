@@ -0,0 +1,48 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+)
+
+// NRGBAPoint32Capacity is number of bytes stored in a point by NRGBAPoint32ReadWriter
+const NRGBAPoint32Capacity = 4
+
+// NRGBAPoint32ReadWriter stores bytes as is in R, G, B and A channels of non-premultiplied color.
+// Unlike SimplePoint32ReadWriter it never produces invalid premultiplied colors, so the payload
+// survives encoders which store colors non-premultiplied, like PNG does. Carrier must keep
+// color.NRGBA as is, for example image.NRGBA
+type NRGBAPoint32ReadWriter struct{}
+
+func (NRGBAPoint32ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return uint64(n.R)<<24 | uint64(n.G)<<16 | uint64(n.B)<<8 | uint64(n.A)
+}
+
+func (NRGBAPoint32ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	return color.NRGBA{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
+}
+
+func (NRGBAPoint32ReadWriter) Bits(_ image.Point) int {
+	return NRGBAPoint32Capacity * 8
+}
+
+// NRGBAPoint64Capacity is number of bytes stored in a point by NRGBAPoint64ReadWriter
+const NRGBAPoint64Capacity = 8
+
+// NRGBAPoint64ReadWriter stores bytes as is in 16-bit channels of non-premultiplied color like
+// NRGBAPoint32ReadWriter does. Carrier must keep color.NRGBA64 as is, for example image.NRGBA64
+type NRGBAPoint64ReadWriter struct{}
+
+func (NRGBAPoint64ReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return uint64(n.R)<<48 | uint64(n.G)<<32 | uint64(n.B)<<16 | uint64(n.A)
+}
+
+func (NRGBAPoint64ReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	return color.NRGBA64{uint16(value >> 48), uint16(value >> 32), uint16(value >> 16), uint16(value)}
+}
+
+func (NRGBAPoint64ReadWriter) Bits(_ image.Point) int {
+	return NRGBAPoint64Capacity * 8
+}
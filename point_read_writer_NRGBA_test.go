@@ -0,0 +1,104 @@
+package imgio
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_NRGBAPoint32ReadWriter_ReadBits(t *testing.T) {
+	tests := []struct {
+		color color.Color
+
+		expectedValue uint64
+	}{
+		{color.NRGBA{'a', 'b', 'c', 'd'}, 'a'<<24 | 'b'<<16 | 'c'<<8 | 'd'},
+		{color.NRGBA{R: 'a'}, 'a' << 24},
+		{color.RGBA{'a', 'b', 'c', 0xff}, 'a'<<24 | 'b'<<16 | 'c'<<8 | 0xff},
+	}
+
+	for i, test := range tests {
+		value := NRGBAPoint32ReadWriter{}.ReadBits(test.color, image.Point{})
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
+	}
+}
+
+func Test_NRGBAPoint32ReadWriter_WriteBits(t *testing.T) {
+	c := NRGBAPoint32ReadWriter{}.WriteBits('a'<<24|'b'<<16|'c'<<8, color.NRGBA{'e', 'f', 'g', 'h'}, image.Point{})
+	require.Equal(t, color.NRGBA{'a', 'b', 'c', 0}, c)
+}
+
+func Test_NRGBAPoint64ReadWriter_ReadWriteBits(t *testing.T) {
+	tests := []uint64{
+		0,
+		0x0102030405060708,
+		0xffff00000000ffff,
+		0x00000000000000ff,
+	}
+
+	for i, value := range tests {
+		c := NRGBAPoint64ReadWriter{}.WriteBits(value, color.NRGBA64{}, image.Point{})
+		require.Equal(t, value, NRGBAPoint64ReadWriter{}.ReadBits(c, image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_NRGBAPointReadWriters_Bits(t *testing.T) {
+	require.Equal(t, NRGBAPoint32Capacity*8, NRGBAPoint32ReadWriter{}.Bits(image.Point{}))
+	require.Equal(t, NRGBAPoint64Capacity*8, NRGBAPoint64ReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_NRGBAPointReadWriters_PNG(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 9)
+
+	tests := []struct {
+		img draw.Image
+		prw PointReadWriter
+	}{
+		{image.NewNRGBA(rect), NRGBAPoint32ReadWriter{}},
+		{image.NewNRGBA64(rect), NRGBAPoint64ReadWriter{}},
+	}
+
+	for i, test := range tests {
+		storage := NewPointStorage(NewImageAccessor(test.img, test.prw), NewSimplePointsSequenceGenerator(rect))
+		data := randomBytes(t, storage.Size())
+		_, err := storage.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		buff := bytes.NewBuffer(nil)
+		require.Nil(t, png.Encode(buff, test.img), "Test index %d", i)
+		decoded, err := png.Decode(buff)
+		require.Nil(t, err, "Test index %d", i)
+
+		storage = NewPointStorage(NewImageAccessor(decoded.(draw.Image), test.prw), NewSimplePointsSequenceGenerator(rect))
+		actual := make([]byte, len(data))
+		_, err = io.ReadFull(storage, actual)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+	}
+}
+
+func Test_SimplePoint32ReadWriter_PNG_Corrupted(t *testing.T) {
+	rect := image.Rect(0, 0, 16, 9)
+	img := image.NewRGBA(rect)
+	storage := NewImage(img, NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+	data := bytes.Repeat([]byte{0xff, 0xff, 0xff, 0x10}, int(storage.Size()/4))
+	_, err := storage.Write(data)
+	require.Nil(t, err)
+
+	buff := bytes.NewBuffer(nil)
+	require.Nil(t, png.Encode(buff, img))
+	decoded, err := png.Decode(buff)
+	require.Nil(t, err)
+
+	storage = NewImage(decoded.(draw.Image), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+	actual := make([]byte, len(data))
+	_, err = io.ReadFull(storage, actual)
+	require.Nil(t, err)
+	require.NotEqual(t, data, actual)
+}
@@ -0,0 +1,43 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+)
+
+// PointReadWriterNYCbCrABits stores bits in the lowest bits of luma and alpha of a point. Chroma
+// is left intact, because chroma samples may be shared by several points
+type PointReadWriterNYCbCrABits struct {
+	bits int
+}
+
+// PointReadWriterNYCbCrABitsMax is the greatest number of bits per plane of NYCbCrA codec
+const PointReadWriterNYCbCrABitsMax = 8
+
+func NewPointReadWriterNYCbCrABits(bits int) (PointReadWriterNYCbCrABits, error) {
+	if bits < ChannelBitsMin || bits > PointReadWriterNYCbCrABitsMax {
+		return PointReadWriterNYCbCrABits{}, ErrChannelBits
+	}
+
+	return PointReadWriterNYCbCrABits{
+		bits: bits,
+	}, nil
+}
+
+func (prw PointReadWriterNYCbCrABits) ReadBits(c color.Color, p image.Point) uint64 {
+	nycbcra := color.NYCbCrAModel.Convert(c).(color.NYCbCrA)
+	mask := bitsMask(prw.bits)
+	return (uint64(nycbcra.Y)&mask)<<uint(prw.bits) | uint64(nycbcra.A)&mask
+}
+
+func (prw PointReadWriterNYCbCrABits) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	nycbcra := color.NYCbCrAModel.Convert(src).(color.NYCbCrA)
+	mask := bitsMask(prw.bits)
+	nycbcra.A = nycbcra.A&^uint8(mask) | uint8(value&mask)
+	nycbcra.Y = nycbcra.Y&^uint8(mask) | uint8(value>>uint(prw.bits)&mask)
+	return nycbcra
+}
+
+func (prw PointReadWriterNYCbCrABits) Bits(_ image.Point) int {
+	return 2 * prw.bits
+}
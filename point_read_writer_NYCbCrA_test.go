@@ -0,0 +1,52 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_NewPointReadWriterNYCbCrABits(t *testing.T) {
+	tests := []struct {
+		bits int
+
+		expectedErr error
+	}{
+		{0, ErrChannelBits},
+		{1, nil},
+		{8, nil},
+		{9, ErrChannelBits},
+	}
+
+	for i, test := range tests {
+		prw, err := NewPointReadWriterNYCbCrABits(test.bits)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		if err == nil {
+			require.Equal(t, 2*test.bits, prw.Bits(image.Point{}), "Test index %d", i)
+		}
+	}
+}
+
+func Test_PointReadWriterNYCbCrABits_ReadWriteBits(t *testing.T) {
+	tests := []struct {
+		bits  int
+		value uint64
+		src   color.NYCbCrA
+
+		expectedColor color.NYCbCrA
+	}{
+		{1, 2, color.NYCbCrA{color.YCbCr{0x80, 0x11, 0x22}, 0xff}, color.NYCbCrA{color.YCbCr{0x81, 0x11, 0x22}, 0xfe}},
+		{3, 0x2d, color.NYCbCrA{color.YCbCr{0, 0x11, 0x22}, 0}, color.NYCbCrA{color.YCbCr{5, 0x11, 0x22}, 5}},
+		{8, 0xabcd, color.NYCbCrA{color.YCbCr{0x12, 0x11, 0x22}, 0x34}, color.NYCbCrA{color.YCbCr{0xab, 0x11, 0x22}, 0xcd}},
+	}
+
+	for i, test := range tests {
+		prw, err := NewPointReadWriterNYCbCrABits(test.bits)
+		require.Nil(t, err, "Test index %d", i)
+		c := prw.WriteBits(test.value, test.src, image.Point{})
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+		require.Equal(t, test.value, prw.ReadBits(c, image.Point{}), "Test index %d", i)
+	}
+}
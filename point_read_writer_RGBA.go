@@ -6,25 +6,24 @@ import (
 )
 
 type PointReadWriterRGBA interface {
-	Read(start int, c color.RGBA, p image.Point) ([]byte, int)
-	Write(b []byte, start int, src color.RGBA, p image.Point) (color.RGBA, int)
-	Size(p image.Point) int64
+	ReadBits(c color.RGBA, p image.Point) uint64
+	WriteBits(value uint64, src color.RGBA, p image.Point) color.RGBA
+	Bits(p image.Point) int
 }
 
+// PointReadWriterRGBASimple stores bytes as is in R, G, B and A channels
 type PointReadWriterRGBASimple struct{}
 
-const PointReadWriterRGBASimpleCapacity = 1
+const PointReadWriterRGBASimpleCapacity = 4
 
-func (PointReadWriterRGBASimple) Read(start int, c color.RGBA, p image.Point) ([]byte, int) {
-	// TODO: Implement method.
-	return []byte{}, 0
+func (PointReadWriterRGBASimple) ReadBits(c color.RGBA, p image.Point) uint64 {
+	return uint64(c.R)<<24 | uint64(c.G)<<16 | uint64(c.B)<<8 | uint64(c.A)
 }
 
-func (PointReadWriterRGBASimple) Write(b []byte, start int, src color.RGBA, p image.Point) (color.RGBA, int) {
-	// TODO: Implement method.
-	return color.RGBA{}, 0
+func (PointReadWriterRGBASimple) WriteBits(value uint64, src color.RGBA, p image.Point) color.RGBA {
+	return color.RGBA{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
 }
 
-func (PointReadWriterRGBASimple) Size(_ image.Point) int64 {
-	return PointReadWriterRGBASimpleCapacity
+func (PointReadWriterRGBASimple) Bits(_ image.Point) int {
+	return PointReadWriterRGBASimpleCapacity * 8
 }
@@ -1 +1,50 @@
 package imgio
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_PointReadWriterRGBASimple_ReadBits(t *testing.T) {
+	tests := []struct {
+		color color.RGBA
+		point image.Point
+
+		expectedValue uint64
+	}{
+		{color.RGBA{'a', 'b', 'c', 'd'}, image.Point{}, 'a'<<24 | 'b'<<16 | 'c'<<8 | 'd'},
+		{color.RGBA{R: 'a'}, image.Point{}, 'a' << 24},
+		{color.RGBA{B: 'a', A: 'b'}, image.Point{}, 'a'<<8 | 'b'},
+	}
+
+	for i, test := range tests {
+		value := PointReadWriterRGBASimple{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
+	}
+}
+
+func Test_PointReadWriterRGBASimple_WriteBits(t *testing.T) {
+	tests := []struct {
+		value uint64
+		color color.RGBA
+		point image.Point
+
+		expectedColor color.RGBA
+	}{
+		{'a'<<24 | 'b'<<16 | 'c'<<8 | 'd', color.RGBA{}, image.Point{}, color.RGBA{'a', 'b', 'c', 'd'}},
+		{'a'<<8 | 'b', color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, color.RGBA{0, 0, 'a', 'b'}},
+		{0, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, color.RGBA{}},
+	}
+
+	for i, test := range tests {
+		c := PointReadWriterRGBASimple{}.WriteBits(test.value, test.color, test.point)
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+	}
+}
+
+func Test_PointReadWriterRGBASimple_Bits(t *testing.T) {
+	require.Equal(t, PointReadWriterRGBASimpleCapacity*8, PointReadWriterRGBASimple{}.Bits(image.Point{}))
+}
@@ -6,51 +6,88 @@ import (
 )
 
 type PointReadWriterYCbCr interface {
-	Read(start int, c color.YCbCr, p image.Point) ([]byte, int)
-	Write(b []byte, start int, src color.YCbCr, p image.Point) (color.YCbCr, int)
-	Size(p image.Point) int64
+	ReadBits(c color.YCbCr, p image.Point) uint64
+	WriteBits(value uint64, src color.YCbCr, p image.Point) color.YCbCr
+	Bits(p image.Point) int
+}
+
+// PointReadWriterYCbCrPlanes is implemented by YCbCr read-writers which tell how many of bits of a
+// point are stored in luma and how many in chroma. Luma bits are the highest bits of the value of
+// a point. Read-writers which don't implement it are considered to store all bits in chroma
+type PointReadWriterYCbCrPlanes interface {
+	LumaBits(p image.Point) int
+	ChromaBits(p image.Point) int
+}
+
+// ycbcrPlanesBits returns number of bits which prw stores in luma and chroma of point p
+func ycbcrPlanesBits(prw PointReadWriterYCbCr, p image.Point) (luma, chroma int) {
+	if planes, ok := prw.(PointReadWriterYCbCrPlanes); ok {
+		return planes.LumaBits(p), planes.ChromaBits(p)
+	}
+	return 0, prw.Bits(p)
 }
 
 type PointReadWriterYCbCrSimple struct {
 	Y uint8
 }
 
-const PointReadWriterYCbCrSimpleCapacity = 1
+const PointReadWriterYCbCrSimpleCapacity = 2
 
-func (PointReadWriterYCbCrSimple) Read(start int, c color.YCbCr, p image.Point) ([]byte, int) {
-	if start >= PointReadWriterYCbCrSimpleCapacity {
-		return []byte{}, 0
-	}
+func (PointReadWriterYCbCrSimple) ReadBits(c color.YCbCr, p image.Point) uint64 {
+	return uint64(c.Cb)<<8 | uint64(c.Cr)
+}
 
-	dst := make([]byte, PointReadWriterYCbCrSimpleCapacity-start)
-	src := []byte{c.Cb, c.Cr}
+func (prw PointReadWriterYCbCrSimple) WriteBits(value uint64, src color.YCbCr, p image.Point) color.YCbCr {
+	return color.YCbCr{prw.Y, byte(value >> 8), byte(value)}
+}
 
-	return dst, copy(dst, src[start:])
+func (PointReadWriterYCbCrSimple) Bits(_ image.Point) int {
+	return PointReadWriterYCbCrSimpleCapacity * 8
 }
 
-func (prw PointReadWriterYCbCrSimple) Write(b []byte, start int, src color.YCbCr, p image.Point) (color.YCbCr, int) {
-	dst := color.YCbCr{src.Y, src.Cb, src.Cr}
+func (PointReadWriterYCbCrSimple) LumaBits(_ image.Point) int {
+	return 0
+}
 
-	if start >= SimplePoint32Capacity {
-		return dst, 0
-	}
+func (PointReadWriterYCbCrSimple) ChromaBits(_ image.Point) int {
+	return PointReadWriterYCbCrSimpleCapacity * 8
+}
+
+// PointReadWriterYCbCrLuma stores one bit per point as one of two distant luma levels, so stored
+// bits survive lossy compression of the image in the most cases. Chroma is left intact
+type PointReadWriterYCbCrLuma struct{}
 
-	dst.Y = prw.Y
+const (
+	PointReadWriterYCbCrLumaLow  = 0x30
+	PointReadWriterYCbCrLumaHigh = 0xd0
 
-	addrs := []*uint8{&dst.Cb, &dst.Cr}
+	PointReadWriterYCbCrLumaBits = 1
+)
 
-	n := 0
-	for i, addr := range addrs[start:] {
-		if i >= len(b) {
-			break
-		}
-		*addr = b[i]
-		n++
+func (PointReadWriterYCbCrLuma) ReadBits(c color.YCbCr, p image.Point) uint64 {
+	if c.Y >= (PointReadWriterYCbCrLumaLow+PointReadWriterYCbCrLumaHigh)/2 {
+		return 1
 	}
+	return 0
+}
+
+func (PointReadWriterYCbCrLuma) WriteBits(value uint64, src color.YCbCr, p image.Point) color.YCbCr {
+	if value&1 == 1 {
+		src.Y = PointReadWriterYCbCrLumaHigh
+	} else {
+		src.Y = PointReadWriterYCbCrLumaLow
+	}
+	return src
+}
+
+func (PointReadWriterYCbCrLuma) Bits(_ image.Point) int {
+	return PointReadWriterYCbCrLumaBits
+}
 
-	return dst, n
+func (PointReadWriterYCbCrLuma) LumaBits(_ image.Point) int {
+	return PointReadWriterYCbCrLumaBits
 }
 
-func (PointReadWriterYCbCrSimple) Size(_ image.Point) int64 {
-	return PointReadWriterYCbCrSimpleCapacity
+func (PointReadWriterYCbCrLuma) ChromaBits(_ image.Point) int {
+	return 0
 }
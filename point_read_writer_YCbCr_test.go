@@ -8,56 +8,86 @@ import (
 	"gopkg.in/stretchr/testify.v1/require"
 )
 
-func Test_PointReadWriterYCbCrSimple_Read(t *testing.T) {
+func Test_PointReadWriterYCbCrSimple_ReadBits(t *testing.T) {
 	tests := []struct {
-		startOn int
-		color   color.YCbCr
-		point   image.Point
+		color color.YCbCr
+		point image.Point
 
-		expectedBuff   []byte
-		expectedNumber int
+		expectedValue uint64
 	}{
-		{0, color.YCbCr{0xff, 'b', 'c'}, image.Point{}, []byte{'b', 'c'}, 2},
-		{1, color.YCbCr{Y: 'a'}, image.Point{}, []byte{0}, 1},
-		{1, color.YCbCr{Cb: 'a', Cr: 'b'}, image.Point{}, []byte{'b'}, 1},
-		{3, color.YCbCr{'e', 'f', 'g'}, image.Point{}, []byte{}, 0},
-		{4, color.YCbCr{'e', 'f', 'g'}, image.Point{}, []byte{}, 0},
+		{color.YCbCr{0xff, 'b', 'c'}, image.Point{}, 'b'<<8 | 'c'},
+		{color.YCbCr{Y: 'a'}, image.Point{}, 0},
+		{color.YCbCr{Cb: 'a', Cr: 'b'}, image.Point{}, 'a'<<8 | 'b'},
 	}
 
 	for i, test := range tests {
-		b, n := PointReadWriterYCbCrSimple{}.Read(test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
-		require.Equal(t, test.expectedBuff, b, "Test index %d", i)
+		value := PointReadWriterYCbCrSimple{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
 	}
 }
 
-func Test_PointReadWriterYCbCrSimple_Write(t *testing.T) {
+func Test_PointReadWriterYCbCrSimple_WriteBits(t *testing.T) {
 	tests := []struct {
-		buff    []byte
-		startOn int
-		color   color.YCbCr
-		point   image.Point
+		prw   PointReadWriterYCbCrSimple
+		value uint64
+		color color.YCbCr
+		point image.Point
 
-		expectedColor  color.YCbCr
-		expectedNumber int
+		expectedColor color.YCbCr
 	}{
-		{[]byte{'a', 'b', 'c', 'd'}, 0, color.YCbCr{}, image.Point{}, color.YCbCr{0xff, 'a', 'b'}, 2},
-		{[]byte{'a', 'b', 'c', 'd'}, 1, color.YCbCr{Y: 'a'}, image.Point{}, color.YCbCr{0xff, 0, 'a'}, 1},
-		{[]byte{'a', 'b', 'c', 'd'}, 2, color.YCbCr{Cb: 'a'}, image.Point{}, color.YCbCr{Cb: 'a'}, 0},
-		{[]byte{'a', 'b', 'c', 'd'}, 4, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{'e', 'f', 'g'}, 0},
-		{[]byte{'a', 'b', 'c', 'd', 'e', 'f'}, 0, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0xff, 'b', 'c'}, 3},
-		{[]byte{'a'}, 0, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0xff, 'f', 'g'}, 1},
-		{[]byte{'i'}, 2, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0xff, 'f', 'i'}, 1},
-		{[]byte{}, 0, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0xff, 'f', 'g'}, 0},
+		{PointReadWriterYCbCrSimple{0xff}, 'a'<<8 | 'b', color.YCbCr{}, image.Point{}, color.YCbCr{0xff, 'a', 'b'}},
+		{PointReadWriterYCbCrSimple{}, 'a', color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0, 0, 'a'}},
+		{PointReadWriterYCbCrSimple{0x80}, 0, color.YCbCr{'e', 'f', 'g'}, image.Point{}, color.YCbCr{0x80, 0, 0}},
 	}
 
 	for i, test := range tests {
-		c, n := PointReadWriterYCbCrSimple{}.Write(test.buff, test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
+		c := test.prw.WriteBits(test.value, test.color, test.point)
 		require.Equal(t, test.expectedColor, c, "Test index %d", i)
 	}
 }
 
-func Test_PointReadWriterYCbCrSimple_Size(t *testing.T) {
-	require.EqualValues(t, PointReadWriterYCbCrSimpleCapacity, PointReadWriterYCbCrSimple{}.Size(image.Point{}))
+func Test_PointReadWriterYCbCrSimple_Bits(t *testing.T) {
+	require.Equal(t, PointReadWriterYCbCrSimpleCapacity*8, PointReadWriterYCbCrSimple{}.Bits(image.Point{}))
+}
+
+func Test_PointReadWriterYCbCrLuma_ReadBits(t *testing.T) {
+	tests := []struct {
+		color color.YCbCr
+
+		expectedValue uint64
+	}{
+		{color.YCbCr{PointReadWriterYCbCrLumaLow, 'b', 'c'}, 0},
+		{color.YCbCr{PointReadWriterYCbCrLumaHigh, 'b', 'c'}, 1},
+		{color.YCbCr{0x7f, 0, 0}, 0},
+		{color.YCbCr{0x80, 0, 0}, 1},
+		{color.YCbCr{0xff, 0, 0}, 1},
+		{color.YCbCr{0x00, 0xff, 0xff}, 0},
+	}
+
+	for i, test := range tests {
+		value := PointReadWriterYCbCrLuma{}.ReadBits(test.color, image.Point{})
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
+	}
+}
+
+func Test_PointReadWriterYCbCrLuma_WriteBits(t *testing.T) {
+	tests := []struct {
+		value uint64
+		color color.YCbCr
+
+		expectedColor color.YCbCr
+	}{
+		{1, color.YCbCr{'e', 'f', 'g'}, color.YCbCr{PointReadWriterYCbCrLumaHigh, 'f', 'g'}},
+		{0, color.YCbCr{'e', 'f', 'g'}, color.YCbCr{PointReadWriterYCbCrLumaLow, 'f', 'g'}},
+		{2, color.YCbCr{}, color.YCbCr{PointReadWriterYCbCrLumaLow, 0, 0}},
+	}
+
+	for i, test := range tests {
+		c := PointReadWriterYCbCrLuma{}.WriteBits(test.value, test.color, image.Point{})
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+	}
+}
+
+func Test_PointReadWriterYCbCrLuma_Bits(t *testing.T) {
+	require.Equal(t, PointReadWriterYCbCrLumaBits, PointReadWriterYCbCrLuma{}.Bits(image.Point{}))
 }
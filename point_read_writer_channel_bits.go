@@ -0,0 +1,156 @@
+package imgio
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+type ChannelMask uint8
+
+const (
+	ChannelR ChannelMask = 1 << iota
+	ChannelG
+	ChannelB
+	ChannelA
+
+	ChannelRGB  = ChannelR | ChannelG | ChannelB
+	ChannelRGBA = ChannelRGB | ChannelA
+)
+
+const (
+	ChannelBitsMin = 1
+	ChannelBitsMax = 16
+)
+
+var (
+	ErrChannelBits = errors.New("Invalid number of bits per channel")
+	ErrChannelMask = errors.New("Empty channel mask")
+)
+
+// ChannelBitsReadWriter stores bits in the lowest bits of channels selected by the mask. If number
+// of bits per channel is not greater than 8 bits are stored in 8-bit channels, which are the
+// highest bytes of 16-bit channels, otherwise in 16-bit channels, so images with 16-bit colors
+// such as image.RGBA64 must be used. If the mask has alpha, bits are stored in non-premultiplied
+// channels, because alpha changed apart from other channels makes invalid premultiplied colors,
+// so image.NRGBA or image.NRGBA64 must be used
+type ChannelBitsReadWriter struct {
+	bits int
+	mask ChannelMask
+}
+
+func NewChannelBitsReadWriter(bits int, mask ChannelMask) (ChannelBitsReadWriter, error) {
+	if bits < ChannelBitsMin || bits > ChannelBitsMax {
+		return ChannelBitsReadWriter{}, ErrChannelBits
+	}
+	if mask&ChannelRGBA == 0 {
+		return ChannelBitsReadWriter{}, ErrChannelMask
+	}
+
+	return ChannelBitsReadWriter{
+		bits: bits,
+		mask: mask & ChannelRGBA,
+	}, nil
+}
+
+// channels returns 16-bit channels of color c, they are non-premultiplied if the mask has alpha
+func (prw ChannelBitsReadWriter) channels(c color.Color) []uint32 {
+	if prw.mask&ChannelA == 0 {
+		r, g, b, a := c.RGBA()
+		return []uint32{r, g, b, a}
+	}
+
+	switch c := c.(type) {
+	case color.NRGBA:
+		return []uint32{uint32(c.R) * 0x101, uint32(c.G) * 0x101, uint32(c.B) * 0x101, uint32(c.A) * 0x101}
+	case color.NRGBA64:
+		return []uint32{uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)}
+	}
+
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return []uint32{uint32(n.R), uint32(n.G), uint32(n.B), uint32(n.A)}
+}
+
+func (prw ChannelBitsReadWriter) ReadBits(c color.Color, p image.Point) uint64 {
+	data := prw.channels(c)
+	mask := uint32(bitsMask(prw.bits))
+	var value uint64
+
+	for i, v := range data {
+		if prw.mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		if prw.bits <= 8 {
+			v >>= 8
+		}
+		value = value<<uint(prw.bits) | uint64(v&mask)
+	}
+
+	return value
+}
+
+func (prw ChannelBitsReadWriter) WriteBits(value uint64, src color.Color, p image.Point) color.Color {
+	if prw.mask&ChannelA != 0 {
+		return prw.writeNonPremultiplied(value, src)
+	}
+
+	srcR, srcG, srcB, srcA := src.RGBA()
+	data := []uint32{srcR, srcG, srcB, srcA}
+	mask := uint32(bitsMask(prw.bits))
+
+	if prw.bits <= 8 {
+		for i := range data {
+			data[i] >>= 8
+		}
+	}
+
+	for i := len(data) - 1; i >= 0; i-- {
+		if prw.mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		data[i] = data[i]&^mask | uint32(value)&mask
+		value >>= uint(prw.bits)
+	}
+
+	if prw.bits <= 8 {
+		return &color.RGBA{byte(data[0]), byte(data[1]), byte(data[2]), byte(data[3])}
+	}
+
+	return &color.RGBA64{uint16(data[0]), uint16(data[1]), uint16(data[2]), uint16(data[3])}
+}
+
+// writeNonPremultiplied stores value in non-premultiplied channels of src. Result is color.NRGBA
+// for color.NRGBA source and 8-bit channels, otherwise it is color.NRGBA64 which keeps the low
+// bytes of the source
+func (prw ChannelBitsReadWriter) writeNonPremultiplied(value uint64, src color.Color) color.Color {
+	data := prw.channels(src)
+	mask := uint32(bitsMask(prw.bits))
+	shift := uint(0)
+	if prw.bits <= 8 {
+		shift = 8
+	}
+
+	for i := len(data) - 1; i >= 0; i-- {
+		if prw.mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		data[i] = data[i]&^(mask<<shift) | (uint32(value)&mask)<<shift
+		value >>= uint(prw.bits)
+	}
+
+	if _, ok := src.(color.NRGBA); ok && prw.bits <= 8 {
+		return color.NRGBA{byte(data[0] >> 8), byte(data[1] >> 8), byte(data[2] >> 8), byte(data[3] >> 8)}
+	}
+
+	return color.NRGBA64{uint16(data[0]), uint16(data[1]), uint16(data[2]), uint16(data[3])}
+}
+
+func (prw ChannelBitsReadWriter) Bits(_ image.Point) int {
+	n := 0
+	for mask := prw.mask; mask > 0; mask >>= 1 {
+		if mask&1 == 1 {
+			n++
+		}
+	}
+	return n * prw.bits
+}
@@ -0,0 +1,151 @@
+package imgio
+
+import (
+	"bytes"
+	"crypto/rand"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_NewChannelBitsReadWriter(t *testing.T) {
+	tests := []struct {
+		bits int
+		mask ChannelMask
+
+		expectedErr error
+	}{
+		{1, ChannelRGBA, nil},
+		{16, ChannelR, nil},
+		{0, ChannelRGBA, ErrChannelBits},
+		{17, ChannelRGBA, ErrChannelBits},
+		{4, 0, ErrChannelMask},
+		{4, 0xf0, ErrChannelMask},
+	}
+
+	for i, test := range tests {
+		_, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+	}
+}
+
+func Test_ChannelBitsReadWriter_Bits(t *testing.T) {
+	tests := []struct {
+		bits int
+		mask ChannelMask
+
+		expectedBits int
+	}{
+		{1, ChannelRGBA, 4},
+		{1, ChannelRGB, 3},
+		{3, ChannelG | ChannelA, 6},
+		{16, ChannelRGBA, 64},
+	}
+
+	for i, test := range tests {
+		prw, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedBits, prw.Bits(image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_ChannelBitsReadWriter_ReadBits(t *testing.T) {
+	tests := []struct {
+		bits  int
+		mask  ChannelMask
+		color color.Color
+
+		expectedValue uint64
+	}{
+		{1, ChannelRGBA, color.NRGBA{0xff, 0xfe, 0x01, 0x00}, 0xa},
+		{1, ChannelRGB, color.RGBA{0xff, 0xfe, 0x01, 0x00}, 0x5},
+		{2, ChannelR | ChannelB, color.RGBA{0xfe, 0xff, 0x01, 0xff}, 0x9},
+		{8, ChannelRGBA, color.NRGBA{'a', 'b', 'c', 'd'}, 'a'<<24 | 'b'<<16 | 'c'<<8 | 'd'},
+		{12, ChannelG | ChannelA, color.NRGBA64{0xffff, 0x1234, 0xffff, 0x5678}, 0x234678},
+		{2, ChannelRGBA, color.RGBA{0x01, 0x02, 0x03, 0xff}, 0x1<<6 | 0x2<<4 | 0x3<<2 | 0x3},
+		{3, ChannelRGB, color.RGBA64{0x1234, 0x8000, 0xfff0, 0xffff}, 0x2<<6 | 0x0<<3 | 0x7},
+	}
+
+	for i, test := range tests {
+		prw, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedValue, prw.ReadBits(test.color, image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_ChannelBitsReadWriter_WriteBits(t *testing.T) {
+	tests := []struct {
+		bits  int
+		mask  ChannelMask
+		value uint64
+		color color.Color
+
+		expectedColor color.Color
+	}{
+		{1, ChannelRGBA, 0xc, color.NRGBA{0xfe, 0xff, 0xff, 0x00}, color.NRGBA{0xff, 0xff, 0xfe, 0x00}},
+		{1, ChannelRGB, 0xff, color.RGBA{0x10, 0x10, 0x10, 0x10}, &color.RGBA{0x11, 0x11, 0x11, 0x10}},
+		{4, ChannelB, 0xab, color.RGBA{0x10, 0x10, 0x10, 0x10}, &color.RGBA{0x10, 0x10, 0x1b, 0x10}},
+		{8, ChannelRGBA, 'a'<<24 | 'b'<<16 | 'c'<<8 | 'd', color.NRGBA{}, color.NRGBA{'a', 'b', 'c', 'd'}},
+		{12, ChannelG | ChannelA, 0x234678, color.NRGBA64{0xffff, 0xffff, 0xffff, 0xffff},
+			color.NRGBA64{0xffff, 0xf234, 0xffff, 0xf678}},
+		{2, ChannelRGBA, 0xff, color.RGBA{}, color.NRGBA64{0x0300, 0x0300, 0x0300, 0x0300}},
+		{1, ChannelA, 0x0, color.NRGBA64{0x1234, 0x5678, 0x9abc, 0x4d4c}, color.NRGBA64{0x1234, 0x5678, 0x9abc, 0x4c4c}},
+		{3, ChannelRGB, 0x1ff, color.RGBA64{0x1234, 0x8000, 0xfff0, 0xffff}, &color.RGBA{0x17, 0x87, 0xff, 0xff}},
+	}
+
+	for i, test := range tests {
+		prw, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, test.expectedColor, prw.WriteBits(test.value, test.color, image.Point{}), "Test index %d", i)
+	}
+}
+
+func Test_ChannelBitsReadWriter_Alpha_PNG(t *testing.T) {
+	rect := image.Rect(0, 0, 9, 7)
+	tests := []struct {
+		img  draw.Image
+		pix  []byte
+		bits int
+		mask ChannelMask
+	}{
+		{image.NewNRGBA(rect), nil, 2, ChannelRGBA},
+		{image.NewNRGBA(rect), nil, 1, ChannelG | ChannelA},
+		{image.NewNRGBA64(rect), nil, 12, ChannelRGBA},
+		{image.NewNRGBA64(rect), nil, 3, ChannelA},
+	}
+
+	for i, test := range tests {
+		switch img := test.img.(type) {
+		case *image.NRGBA:
+			test.pix = img.Pix
+		case *image.NRGBA64:
+			test.pix = img.Pix
+		}
+		_, err := rand.Reader.Read(test.pix)
+		require.Nil(t, err, "Test index %d", i)
+
+		prw, err := NewChannelBitsReadWriter(test.bits, test.mask)
+		require.Nil(t, err, "Test index %d", i)
+		img := NewImage(test.img, NewSimplePointsSequenceGenerator(rect), prw)
+		data := make([]byte, img.Size())
+		_, err = rand.Reader.Read(data)
+		require.Nil(t, err, "Test index %d", i)
+		_, err = img.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		buff := bytes.NewBuffer(nil)
+		require.Nil(t, png.Encode(buff, test.img), "Test index %d", i)
+		decoded, err := png.Decode(buff)
+		require.Nil(t, err, "Test index %d", i)
+
+		actual := make([]byte, len(data))
+		_, err = io.ReadFull(NewImage(decoded.(draw.Image), NewSimplePointsSequenceGenerator(rect), prw), actual)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+	}
+}
@@ -1,170 +1,254 @@
 package imgio
 
 import (
+	"crypto/rand"
 	"image"
 	"image/color"
+	"image/draw"
 	"testing"
 
 	"gopkg.in/stretchr/testify.v1/require"
 )
 
-func Test_SimplePoint32ReadWriter_Read(t *testing.T) {
+func Test_SimplePoint32ReadWriter_ReadBits(t *testing.T) {
 	tests := []struct {
-		startOn int
-		color   color.RGBA
-		point   image.Point
+		color color.RGBA
+		point image.Point
 
-		expectedBuff   []byte
-		expectedNumber int
+		expectedValue uint64
 	}{
-		{0, color.RGBA{'a', 'b', 'c', 'd'}, image.Point{}, []byte{'a', 'b', 'c', 'd'}, 4},
-		{1, color.RGBA{R: 'a'}, image.Point{}, []byte{0, 0, 0}, 3},
-		{2, color.RGBA{B: 'a', A: 'b'}, image.Point{}, []byte{'a', 'b'}, 2},
-		{4, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, []byte{}, 0},
+		{color.RGBA{'a', 'b', 'c', 'd'}, image.Point{}, 'a'<<24 | 'b'<<16 | 'c'<<8 | 'd'},
+		{color.RGBA{R: 'a'}, image.Point{}, 'a' << 24},
+		{color.RGBA{B: 'a', A: 'b'}, image.Point{}, 'a'<<8 | 'b'},
+		{color.RGBA{}, image.Point{}, 0},
 	}
 
 	for i, test := range tests {
-		b, n := SimplePoint32ReadWriter{}.Read(test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
-		require.Equal(t, test.expectedBuff, b, "Test index %d", i)
+		value := SimplePoint32ReadWriter{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
 	}
 }
 
-func Test_SimplePoint32ReadWriter_Write(t *testing.T) {
+func Test_SimplePoint32ReadWriter_WriteBits(t *testing.T) {
 	tests := []struct {
-		buff    []byte
-		startOn int
-		color   color.RGBA
-		point   image.Point
+		value uint64
+		color color.RGBA
+		point image.Point
 
-		expectedColor  *color.RGBA
-		expectedNumber int
+		expectedColor *color.RGBA
 	}{
-		{[]byte{'a', 'b', 'c', 'd'}, 0, color.RGBA{}, image.Point{}, &color.RGBA{'a', 'b', 'c', 'd'}, 4},
-		{[]byte{'a', 'b', 'c', 'd'}, 1, color.RGBA{R: 'a'}, image.Point{}, &color.RGBA{'a', 'a', 'b', 'c'}, 3},
-		{[]byte{'a', 'b', 'c', 'd'}, 2, color.RGBA{R: 'a'}, image.Point{}, &color.RGBA{'a', 0, 'a', 'b'}, 2},
-		{[]byte{'a', 'b', 'c', 'd'}, 2, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'e', 'f', 'a', 'b'}, 2},
-		{[]byte{'a', 'b', 'c', 'd'}, 4, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'e', 'f', 'g', 'h'}, 0},
-		{[]byte{'a', 'b', 'c', 'd', 'e', 'f'}, 0, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'a', 'b', 'c', 'd'}, 4},
-		{[]byte{'a'}, 0, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'a', 'f', 'g', 'h'}, 1},
-		{[]byte{'i'}, 2, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'e', 'f', 'i', 'h'}, 1},
-		{[]byte{}, 0, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'e', 'f', 'g', 'h'}, 0},
+		{'a'<<24 | 'b'<<16 | 'c'<<8 | 'd', color.RGBA{}, image.Point{}, &color.RGBA{'a', 'b', 'c', 'd'}},
+		{'a'<<24 | 'b'<<16 | 'c'<<8 | 'd', color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{'a', 'b', 'c', 'd'}},
+		{'x'<<32 | 'a'<<8 | 'b', color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{0, 0, 'a', 'b'}},
+		{0, color.RGBA{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA{}},
 	}
 
 	for i, test := range tests {
-		c, n := SimplePoint32ReadWriter{}.Write(test.buff, test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
+		c := SimplePoint32ReadWriter{}.WriteBits(test.value, test.color, test.point)
 		require.Equal(t, test.expectedColor, c, "Test index %d", i)
 	}
 }
 
-func Test_SimplePoint64ReadWriter_Read(t *testing.T) {
+func Test_SimplePoint32ReadWriter_Bits(t *testing.T) {
+	require.Equal(t, SimplePoint32Capacity*8, SimplePoint32ReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_SimplePoint64ReadWriter_ReadBits(t *testing.T) {
 	tests := []struct {
-		startOn int
-		color   color.RGBA64
-		point   image.Point
+		color color.RGBA64
+		point image.Point
 
-		expectedBuff   []byte
-		expectedNumber int
+		expectedValue uint64
 	}{
-		{0, color.RGBA64{'a'<<8 + 'b', 'c'<<8 + 'b', 'c', 'd'}, image.Point{}, []byte{'a', 'b', 'c', 'b', 0, 'c', 0, 'd'}, 8},
-		{1, color.RGBA64{R: 'a'}, image.Point{}, []byte{'a', 0, 0, 0, 0, 0, 0}, 7},
-		{2, color.RGBA64{R: 'a'}, image.Point{}, []byte{0, 0, 0, 0, 0, 0}, 6},
-		{4, color.RGBA64{B: 'a', A: 'b'}, image.Point{}, []byte{0, 'a', 0, 'b'}, 4},
-		{8, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, []byte{}, 0},
+		{color.RGBA64{'a'<<8 + 'b', 'c'<<8 + 'b', 'c', 'd'}, image.Point{}, ('a'<<8+'b')<<48 | ('c'<<8+'b')<<32 | 'c'<<16 | 'd'},
+		{color.RGBA64{R: 'a'}, image.Point{}, 'a' << 48},
+		{color.RGBA64{B: 'a', A: 'b'}, image.Point{}, 'a'<<16 | 'b'},
+		{color.RGBA64{0xffff, 0xffff, 0xffff, 0xffff}, image.Point{}, 0xffffffffffffffff},
 	}
 
 	for i, test := range tests {
-		b, n := SimplePoint64ReadWriter{}.Read(test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
-		require.Equal(t, test.expectedBuff, b, "Test index %d", i)
+		value := SimplePoint64ReadWriter{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
 	}
 }
 
-func Test_SimplePoint64ReadWriter_Write(t *testing.T) {
+func Test_SimplePoint64ReadWriter_WriteBits(t *testing.T) {
 	tests := []struct {
-		buff    []byte
-		startOn int
-		color   color.RGBA64
-		point   image.Point
+		value uint64
+		color color.RGBA64
+		point image.Point
 
-		expectedColor  *color.RGBA64
-		expectedNumber int
+		expectedColor *color.RGBA64
 	}{
-		{[]byte{'a', 'b', 'c', 'd'}, 0, color.RGBA64{}, image.Point{}, &color.RGBA64{R: 'a'<<8 + 'b', G: 'c'<<8 + 'd'}, 4},
-		{[]byte{'a', 'b', 'c', 'd'}, 1, color.RGBA64{}, image.Point{}, &color.RGBA64{R: 'b', G: 'c'<<8 + 'd'}, 3},
-		{[]byte{'a', 'b', 'c', 'd'}, 2, color.RGBA64{R: 'f'}, image.Point{}, &color.RGBA64{'f', 'a'<<8 + 'b', 'c'<<8 + 'd', 0}, 4},
-		{[]byte{'a', 'b', 'c', 'd'}, 6, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'e', 'f', 'g', 'a'<<8 + 'b'}, 2},
-		{[]byte{'a', 'b', 'c', 'd'}, 8, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'e', 'f', 'g', 'h'}, 0},
-		{[]byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j'}, 0, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{},
-			&color.RGBA64{'a'<<8 + 'b', 'c'<<8 + 'd', 'e'<<8 + 'f', 'g'<<8 + 'h'}, 8},
-		{[]byte{'a'}, 0, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'a'<<8 + 'e', 'f', 'g', 'h'}, 1},
-		{[]byte{0, 'a'}, 0, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'a', 'f', 'g', 'h'}, 2},
-		{[]byte{'i'}, 2, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'e', 'i'<<8 + 'f', 'g', 'h'}, 1},
-		{[]byte{}, 2, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'e', 'f', 'g', 'h'}, 0},
-		{[]byte{}, 0, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{'e', 'f', 'g', 'h'}, 0},
+		{('a'<<8 + 'b') << 48, color.RGBA64{}, image.Point{}, &color.RGBA64{R: 'a'<<8 + 'b'}},
+		{('a'<<8+'b')<<48 | ('c'<<8+'d')<<32 | ('e'<<8+'f')<<16 | ('g'<<8 + 'h'), color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{},
+			&color.RGBA64{'a'<<8 + 'b', 'c'<<8 + 'd', 'e'<<8 + 'f', 'g'<<8 + 'h'}},
+		{0xffffffffffffffff, color.RGBA64{}, image.Point{}, &color.RGBA64{0xffff, 0xffff, 0xffff, 0xffff}},
+		{0, color.RGBA64{'e', 'f', 'g', 'h'}, image.Point{}, &color.RGBA64{}},
 	}
 
 	for i, test := range tests {
-		c, n := SimplePoint64ReadWriter{}.Write(test.buff, test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
+		c := SimplePoint64ReadWriter{}.WriteBits(test.value, test.color, test.point)
 		require.Equal(t, test.expectedColor, c, "Test index %d", i)
 	}
 }
 
-func Test_GentlePoint16ReadWriter_Read(t *testing.T) {
+func Test_SimplePoint64ReadWriter_Bits(t *testing.T) {
+	require.Equal(t, SimplePoint64Capacity*8, SimplePoint64ReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_GentlePoint16ReadWriter_ReadBits(t *testing.T) {
 	tests := []struct {
-		startOn int
-		color   color.RGBA
-		point   image.Point
+		color color.RGBA
+		point image.Point
 
-		expectedBuff   []byte
-		expectedNumber int
+		expectedValue uint64
 	}{
-		{0, color.RGBA{
-			'x'&0xf0 | 'a'&0xf0>>4, 'x'&0xf0 | 'a'&0x0f,
-			'x'&0xf0 | 'b'&0xf0>>4, 'x'&0xf0 | 'b'&0x0f,
-		}, image.Point{}, []byte{'a', 'b'}, 2},
-		{1, color.RGBA{R: 'x'&0xf0 + 'a'&0xf0>>4, G: 'x'&0xf0 + 'a'&0x0f}, image.Point{}, []byte{0}, 1},
-		{1, color.RGBA{
+		{color.RGBA{
 			'x'&0xf0 | 'a'&0xf0>>4, 'x'&0xf0 | 'a'&0x0f,
 			'x'&0xf0 | 'b'&0xf0>>4, 'x'&0xf0 | 'b'&0x0f,
-		}, image.Point{}, []byte{'b'}, 1},
-		{2, color.RGBA{}, image.Point{}, []byte{}, 0},
+		}, image.Point{}, 'a'<<8 | 'b'},
+		{color.RGBA{R: 'x'&0xf0 + 'a'&0xf0>>4, G: 'x'&0xf0 + 'a'&0x0f}, image.Point{}, 'a' << 8},
+		{color.RGBA{0xf0, 0xf0, 0xf0, 0xf0}, image.Point{}, 0},
 	}
 
 	for i, test := range tests {
-		b, n := GentlePoint16ReadWriter{}.Read(test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
-		require.Equal(t, test.expectedBuff, b, "Test index %d", i)
+		value := GentlePoint16ReadWriter{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
 	}
 }
 
-func Test_GentlePoint16ReadWriter_Write(t *testing.T) {
+func Test_GentlePoint16ReadWriter_WriteBits(t *testing.T) {
 	tests := []struct {
-		buff    []byte
-		startOn int
-		color   color.RGBA
-		point   image.Point
+		value uint64
+		color color.RGBA
+		point image.Point
 
-		expectedColor  *color.RGBA
-		expectedNumber int
+		expectedColor *color.RGBA
 	}{
-		{[]byte{'a', 'b', 'c', 'd'}, 0, color.RGBA{}, image.Point{}, &color.RGBA{
+		{'a'<<8 | 'b', color.RGBA{}, image.Point{}, &color.RGBA{
 			'a' & 0xf0 >> 4, 'a' & 0x0f,
 			'b' & 0xf0 >> 4, 'b' & 0x0f,
-		}, 2},
-		{[]byte{'a', 'b', 'c', 'd'}, 1, color.RGBA{}, image.Point{}, &color.RGBA{0, 0, 'a' & 0xf0 >> 4, 'a' & 0x0f}, 1},
-		{[]byte{'a', 'b', 'c', 'd'}, 2, color.RGBA{}, image.Point{}, &color.RGBA{}, 0},
-		{[]byte{}, 2, color.RGBA{}, image.Point{}, &color.RGBA{}, 0},
-		{[]byte{}, 1, color.RGBA{}, image.Point{}, &color.RGBA{}, 0},
-		{[]byte{}, 0, color.RGBA{}, image.Point{}, &color.RGBA{}, 0},
-		{[]byte{}, 0, color.RGBA{'a', 'b', 'c', 'd'}, image.Point{}, &color.RGBA{'a', 'b', 'c', 'd'}, 0},
+		}},
+		{'a'<<8 | 'b', color.RGBA{0xf0, 0xf0, 0xf0, 0xff}, image.Point{}, &color.RGBA{
+			0xf0 | 'a'&0xf0>>4, 0xf0 | 'a'&0x0f,
+			0xf0 | 'b'&0xf0>>4, 0xf0 | 'b'&0x0f,
+		}},
+		{0, color.RGBA{'a', 'b', 'c', 'd'}, image.Point{}, &color.RGBA{'a' & 0xf0, 'b' & 0xf0, 'c' & 0xf0, 'd' & 0xf0}},
 	}
 
 	for i, test := range tests {
-		c, n := GentlePoint16ReadWriter{}.Write(test.buff, test.startOn, test.color, test.point)
-		require.Equal(t, test.expectedNumber, n, "Test index %d", i)
+		c := GentlePoint16ReadWriter{}.WriteBits(test.value, test.color, test.point)
 		require.Equal(t, test.expectedColor, c, "Test index %d", i)
 	}
 }
+
+func Test_GentlePoint16ReadWriter_Bits(t *testing.T) {
+	require.Equal(t, GentlePoint16Capacity*8, GentlePoint16ReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_SmartPoint8ReadWriter_ReadBits(t *testing.T) {
+	tests := []struct {
+		color color.RGBA
+		point image.Point
+
+		expectedValue uint64
+	}{
+		{color.RGBA{
+			'x'&0xf8 | 'a'&0xe0>>5, 'x'&0xf8 | 'a'&0x1c>>2, 'x'&0xfc | 'a'&0x03, 0xff,
+		}, image.Point{}, 'a'},
+		{color.RGBA{0xff, 0xff, 0xff, 0xff}, image.Point{}, 0xff},
+		{color.RGBA{0xf8, 0xf8, 0xfc, 0xff}, image.Point{}, 0},
+	}
+
+	for i, test := range tests {
+		value := SmartPoint8ReadWriter{}.ReadBits(test.color, test.point)
+		require.Equal(t, test.expectedValue, value, "Test index %d", i)
+	}
+}
+
+func Test_SmartPoint8ReadWriter_WriteBits(t *testing.T) {
+	tests := []struct {
+		value uint64
+		color color.RGBA
+		point image.Point
+
+		expectedColor *color.RGBA
+	}{
+		{'a', color.RGBA{}, image.Point{}, &color.RGBA{
+			'a' & 0xe0 >> 5, 'a' & 0x1c >> 2, 'a' & 0x03, 0,
+		}},
+		{0xff, color.RGBA{0x80, 0x80, 0x80, 0xff}, image.Point{}, &color.RGBA{0x87, 0x87, 0x83, 0xff}},
+		{0, color.RGBA{0xff, 0xff, 0xff, 0xff}, image.Point{}, &color.RGBA{0xf8, 0xf8, 0xfc, 0xff}},
+	}
+
+	for i, test := range tests {
+		c := SmartPoint8ReadWriter{}.WriteBits(test.value, test.color, test.point)
+		require.Equal(t, test.expectedColor, c, "Test index %d", i)
+	}
+}
+
+func Test_SmartPoint8ReadWriter_Bits(t *testing.T) {
+	require.Equal(t, SmartPoint8Capacity*8, SmartPoint8ReadWriter{}.Bits(image.Point{}))
+}
+
+func Test_SmartPoint8ReadWriter_Carriers(t *testing.T) {
+	rect := image.Rect(0, 0, 7, 5)
+	tests := []struct {
+		img   draw.Image
+		color color.Color
+		diff  uint32
+	}{
+		{image.NewRGBA(rect), color.RGBA{200, 100, 50, 0xff}, 7 * 0x101},
+		{image.NewRGBA64(rect), color.RGBA64{0x1234, 0x8000, 0xfff0, 0xffff}, 7 << 8},
+		{image.NewNRGBA(rect), color.NRGBA{200, 100, 50, 77}, 7*0x101*77/0xff + 0x101},
+		{image.NewNRGBA64(rect), color.NRGBA64{0x1234, 0x8000, 0xfff0, 0x4d4d}, 7<<8*0x4d4d/0xffff + 0x101},
+	}
+
+	for i, test := range tests {
+		draw.Draw(test.img, rect, image.NewUniform(test.color), image.ZP, draw.Src)
+		img := NewImage(test.img, NewSimplePointsSequenceGenerator(rect), SmartPoint8ReadWriter{})
+
+		data := make([]byte, img.Size())
+		_, err := rand.Read(data)
+		require.Nil(t, err, "Test index %d", i)
+		_, err = img.Write(data)
+		require.Nil(t, err, "Test index %d", i)
+
+		actual := make([]byte, len(data))
+		_, err = img.ReadAt(actual, 0)
+		require.Nil(t, err, "Test index %d", i)
+		require.Equal(t, data, actual, "Test index %d", i)
+
+		r0, g0, b0, a0 := test.color.RGBA()
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				r, g, b, a := test.img.At(x, y).RGBA()
+				require.Equal(t, a0, a, "Test index %d", i)
+				for _, channel := range [][2]uint32{{r0, r}, {g0, g}, {b0, b}} {
+					require.True(t, channel[0]-channel[1] <= test.diff || channel[1]-channel[0] <= test.diff,
+						"Test index %d", i)
+				}
+			}
+		}
+	}
+}
+
+func Test_SmartPoint8ReadWriter_NonRGBCarriers(t *testing.T) {
+	rect := image.Rect(0, 0, 1, 1)
+	tests := []draw.Image{
+		image.NewGray(rect),
+		image.NewCMYK(rect),
+	}
+
+	for i, carrier := range tests {
+		img := NewImage(carrier, NewSimplePointsSequenceGenerator(rect), SmartPoint8ReadWriter{})
+		_, err := img.Write([]byte{0xa5})
+		require.Nil(t, err, "Test index %d", i)
+
+		actual := make([]byte, 1)
+		_, err = img.ReadAt(actual, 0)
+		require.Nil(t, err, "Test index %d", i)
+		require.NotEqual(t, []byte{0xa5}, actual, "Test index %d", i)
+	}
+}
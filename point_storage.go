@@ -0,0 +1,187 @@
+package imgio
+
+import (
+	"image"
+	"sync"
+)
+
+// PointAccessor reads and writes bits stored in points of a carrier. It hides how a pixel of a
+// particular image type is fetched and stored, so one streaming engine serves every carrier
+type PointAccessor interface {
+	// PointBits returns number of bits stored in point p
+	PointBits(p image.Point) int
+	// ReadPointBits returns bits stored in point p in the lowest PointBits(p) bits
+	ReadPointBits(p image.Point) uint64
+	// WritePointBits stores the lowest PointBits(p) bits of value in point p
+	WritePointBits(p image.Point, value uint64)
+}
+
+// boundedGenerator is implemented by generators which visit every point of a rectangle exactly
+// once, so bits of their points can be counted in order of the rectangle
+type boundedGenerator interface {
+	Bounds() image.Rectangle
+}
+
+// pointsLayout describes how bits are distributed over points of a storage. Number of bits of a
+// point depends only on the accessor and the point, so the layout is computed once
+type pointsLayout struct {
+	// pointBits is number of bits of every point or 0 if points keep different numbers of bits
+	pointBits int
+	totalBits int64
+}
+
+// newPointsLayout counts bits of points of generator gen with accessor acc. Points of bounded
+// generators are visited in order of their rectangle, which is much cheaper than computing
+// points of the sequence
+func newPointsLayout(acc PointAccessor, gen PointsSequenceGenerator) pointsLayout {
+	layout := pointsLayout{pointBits: -1}
+
+	add := func(p image.Point) {
+		bits := acc.PointBits(p)
+		if layout.pointBits == -1 {
+			layout.pointBits = bits
+		} else if layout.pointBits != bits {
+			layout.pointBits = 0
+		}
+		layout.totalBits += int64(bits)
+	}
+
+	if bounded, ok := gen.(boundedGenerator); ok {
+		rect := bounded.Bounds()
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				add(image.Pt(x, y))
+			}
+		}
+	} else {
+		for index, length := uint64(0), gen.Len(); index < length; index++ {
+			add(gen.Point(index))
+		}
+	}
+
+	if layout.pointBits < 0 {
+		layout.pointBits = 0
+	}
+
+	return layout
+}
+
+// pointAt returns index of the point in the sequence of generator gen which contains the first
+// bit of byte on position pos and bit cursor inside that point. It is computed arithmetically if
+// every point keeps the same number of bits
+func (l pointsLayout) pointAt(acc PointAccessor, gen PointsSequenceGenerator, pos int64) (uint64, int) {
+	if l.pointBits > 0 {
+		bitPos := pos * 8
+		return uint64(bitPos / int64(l.pointBits)), int(bitPos % int64(l.pointBits))
+	}
+	return bitPointAt(acc, gen, pos)
+}
+
+// PointStorage is the streaming engine of carriers of any type. Points are visited in order of
+// generator gen and their bits are read and written with accessor acc. It keeps position of the
+// stream and bit cursor of the current point. Carriers embed it
+type PointStorage struct {
+	acc PointAccessor
+	gen PointsSequenceGenerator
+
+	mux       sync.RWMutex
+	bitCursor int
+	position  int64
+
+	layoutOnce sync.Once
+	layout     pointsLayout
+}
+
+func NewPointStorage(acc PointAccessor, gen PointsSequenceGenerator) *PointStorage {
+	return &PointStorage{
+		acc: acc,
+		gen: gen,
+	}
+}
+
+// pointsLayout returns layout of bits of points of the storage
+func (s *PointStorage) pointsLayout() pointsLayout {
+	s.layoutOnce.Do(func() {
+		s.layout = newPointsLayout(s.acc, s.gen)
+	})
+	return s.layout
+}
+
+// Read implements io.Reader interface
+func (s *PointStorage) Read(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n, s.bitCursor, err = readBits(s.acc, s.gen, s.bitCursor, p)
+	s.position += int64(n)
+	return
+}
+
+// Write implements io.Writer interface
+func (s *PointStorage) Write(p []byte) (n int, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n, s.bitCursor, err = writeBits(s.acc, s.gen, s.bitCursor, p)
+	s.position += int64(n)
+	return
+}
+
+// Seek implements io.Seeker interface
+func (s *PointStorage) Seek(offset int64, whence int) (int64, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	pos, err := seekPosition(offset, whence, s.position, s.Size())
+	if err != nil {
+		return s.position, err
+	}
+
+	var index uint64
+	index, s.bitCursor = s.pointsLayout().pointAt(s.acc, s.gen, pos)
+	s.gen.Seek(index)
+	s.position = pos
+
+	return pos, nil
+}
+
+// ReadAt implements io.ReaderAt interface. It doesn't move the cursor of the storage
+func (s *PointStorage) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	index, bitCursor := s.pointsLayout().pointAt(s.acc, s.gen, off)
+	n, _, err = readBits(s.acc, &indexIterator{s.gen, index}, bitCursor, p)
+	if n == len(p) {
+		err = nil
+	}
+	return
+}
+
+// WriteAt implements io.WriterAt interface. It doesn't move the cursor of the storage
+func (s *PointStorage) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrSeekNegative
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	index, bitCursor := s.pointsLayout().pointAt(s.acc, s.gen, off)
+	n, _, err = writeBits(s.acc, &indexIterator{s.gen, index}, bitCursor, p)
+	return
+}
+
+// Size returns number of whole bytes which can be stored in the storage
+func (s *PointStorage) Size() int64 {
+	return s.pointsLayout().totalBits / 8
+}
+
+// Accessor returns accessor of points of the carrier
+func (s *PointStorage) Accessor() PointAccessor {
+	return s.acc
+}
@@ -0,0 +1,63 @@
+package imgio
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_PointStorage_Write(t *testing.T) {
+	rect := image.Rect(0, 0, 5, 2)
+	img := image.NewRGBA(rect)
+	storage := NewPointStorage(NewRGBAAccessor(img, PointReadWriterRGBASimple{}), NewSimplePointsSequenceGenerator(rect))
+
+	n, err := storage.Write([]byte("testing"))
+	require.Equal(t, 7, n)
+	require.Nil(t, err)
+
+	require.Equal(t, color.RGBA{'t', 'e', 's', 't'}, img.RGBAAt(0, 0))
+	require.Equal(t, color.RGBA{'i', 'n', 'g', 0}, img.RGBAAt(1, 0))
+	require.Equal(t, int64(7), storage.position)
+}
+
+func Test_PointStorage_Overflow(t *testing.T) {
+	rect := image.Rect(0, 0, 3, 3)
+	storages := []*PointStorage{
+		NewPointStorage(NewDrawImageAccessor(image.NewRGBA(rect), SmartPoint8ReadWriter{}), NewSimplePointsSequenceGenerator(rect)),
+		NewPointStorage(NewYCbCrAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio444), PointReadWriterYCbCrSimple{}), NewSimplePointsSequenceGenerator(rect)),
+	}
+
+	for i, storage := range storages {
+		size := storage.Size()
+		n, err := storage.Write(make([]byte, size+1))
+		require.Equal(t, ErrOverflow, err, "Test index %d", i)
+		require.Equal(t, int(size), n, "Test index %d", i)
+	}
+}
+
+func Test_PointStorage_SeekReadAt(t *testing.T) {
+	rect := image.Rect(0, 0, 4, 4)
+	storage := NewPointStorage(NewDrawImageAccessor(image.NewRGBA(rect), GentlePoint16ReadWriter{}), NewSimplePointsSequenceGenerator(rect))
+
+	_, err := storage.WriteAt([]byte("data"), 5)
+	require.Nil(t, err)
+
+	pos, err := storage.Seek(-27, io.SeekEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(5), pos)
+
+	buff := make([]byte, 4)
+	_, err = io.ReadFull(storage, buff)
+	require.Nil(t, err)
+	require.Equal(t, []byte("data"), buff)
+
+	n, err := storage.ReadAt(buff, storage.Size()-2)
+	require.Equal(t, 2, n)
+	require.Equal(t, io.EOF, err)
+
+	_, err = storage.ReadAt(buff, -1)
+	require.Equal(t, ErrSeekNegative, err)
+}
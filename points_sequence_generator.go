@@ -1,6 +1,10 @@
 package imgio
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
 	"image"
 	"sync/atomic"
 )
@@ -11,6 +15,10 @@ type PointsSequenceGenerator interface {
 	Rewind()
 	Valid() bool
 	Seek(offset uint64)
+	// Point returns point on position index in the sequence without moving the cursor
+	Point(index uint64) image.Point
+	// Len returns number of points in the sequence
+	Len() uint64
 }
 
 type SimplePointsSequenceGenerator struct {
@@ -26,11 +34,7 @@ func NewSimplePointsSequenceGenerator(rect image.Rectangle) *SimplePointsSequenc
 }
 
 func (spsg *SimplePointsSequenceGenerator) Current() image.Point {
-	p := image.Point{}
-	cursor := atomic.LoadUint64(&spsg.cursor)
-	p.X = int(cursor % uint64(spsg.rect.Size().X))
-	p.Y = int((cursor - uint64(p.X)) / uint64(spsg.rect.Size().X))
-	return spsg.rect.Min.Add(p)
+	return spsg.Point(atomic.LoadUint64(&spsg.cursor))
 }
 
 func (spsg *SimplePointsSequenceGenerator) Next() {
@@ -42,34 +46,133 @@ func (spsg *SimplePointsSequenceGenerator) Rewind() {
 }
 
 func (spsg *SimplePointsSequenceGenerator) Valid() bool {
-	return spsg.cursor < uint64(spsg.rect.Size().X*spsg.rect.Size().Y)
+	return atomic.LoadUint64(&spsg.cursor) < spsg.Len()
 }
 
 func (spsg *SimplePointsSequenceGenerator) Seek(offset uint64) {
 	atomic.StoreUint64(&spsg.cursor, offset)
 }
 
+func (spsg *SimplePointsSequenceGenerator) Point(index uint64) image.Point {
+	p := image.Point{}
+	p.X = int(index % uint64(spsg.rect.Size().X))
+	p.Y = int((index - uint64(p.X)) / uint64(spsg.rect.Size().X))
+	return spsg.rect.Min.Add(p)
+}
+
+func (spsg *SimplePointsSequenceGenerator) Len() uint64 {
+	return uint64(spsg.rect.Size().X * spsg.rect.Size().Y)
+}
+
+// Bounds returns rectangle which points the generator visits
+func (spsg *SimplePointsSequenceGenerator) Bounds() image.Rectangle {
+	return spsg.rect
+}
+
+// randPointsFeistelRounds is number of rounds of Feistel network used to permute points
+const randPointsFeistelRounds = 4
+
+// RandPointsSequenceGenerator visits every point of a rectangle exactly once in a pseudo-random
+// order derived from a key. The order is a keyed permutation of point indexes built on a
+// balanced Feistel network with cycle walking, so any point is computed in O(1) without
+// storing a shuffled table of indexes
 type RandPointsSequenceGenerator struct {
+	rect   image.Rectangle
+	cursor uint64
+
+	block    cipher.Block
+	halfBits uint
+	halfMask uint64
+}
+
+func NewRandPointsSequenceGenerator(rect image.Rectangle, key []byte) *RandPointsSequenceGenerator {
+	sum := sha256.Sum256(key)
+	// Key has valid length so error can be omitted
+	block, _ := aes.NewCipher(sum[:])
+
+	length := uint64(rect.Size().X * rect.Size().Y)
+	halfBits := uint(1)
+	for uint64(1)<<(2*halfBits) < length {
+		halfBits++
+	}
+
+	return &RandPointsSequenceGenerator{
+		rect:     rect,
+		cursor:   0,
+		block:    block,
+		halfBits: halfBits,
+		halfMask: uint64(1)<<halfBits - 1,
+	}
 }
 
 func (rpsg *RandPointsSequenceGenerator) Current() image.Point {
-	// TODO: Implement method.
-	return image.Point{}
+	return rpsg.Point(atomic.LoadUint64(&rpsg.cursor))
 }
 
 func (rpsg *RandPointsSequenceGenerator) Next() {
-	// TODO: Implement method.
+	atomic.AddUint64(&rpsg.cursor, 1)
 }
 
 func (rpsg *RandPointsSequenceGenerator) Rewind() {
-	// TODO: Implement method.
+	atomic.StoreUint64(&rpsg.cursor, 0)
 }
 
 func (rpsg *RandPointsSequenceGenerator) Valid() bool {
-	// TODO: Implement method.
-	return false
+	return atomic.LoadUint64(&rpsg.cursor) < rpsg.Len()
 }
 
 func (rpsg *RandPointsSequenceGenerator) Seek(offset uint64) {
-	// TODO: Implement method.
+	atomic.StoreUint64(&rpsg.cursor, offset)
+}
+
+func (rpsg *RandPointsSequenceGenerator) Point(index uint64) image.Point {
+	index = rpsg.permute(index)
+	p := image.Point{}
+	p.X = int(index % uint64(rpsg.rect.Size().X))
+	p.Y = int((index - uint64(p.X)) / uint64(rpsg.rect.Size().X))
+	return rpsg.rect.Min.Add(p)
+}
+
+func (rpsg *RandPointsSequenceGenerator) Len() uint64 {
+	return uint64(rpsg.rect.Size().X * rpsg.rect.Size().Y)
+}
+
+// Bounds returns rectangle which points the generator visits
+func (rpsg *RandPointsSequenceGenerator) Bounds() image.Rectangle {
+	return rpsg.rect
+}
+
+// permute maps index to the index of the point in the rectangle. Feistel network permutes
+// values of 2*halfBits bits, values out of range are encrypted again until they get in range
+func (rpsg *RandPointsSequenceGenerator) permute(index uint64) uint64 {
+	length := rpsg.Len()
+	if index >= length {
+		return index
+	}
+
+	for {
+		index = rpsg.feistel(index)
+		if index < length {
+			return index
+		}
+	}
+}
+
+func (rpsg *RandPointsSequenceGenerator) feistel(value uint64) uint64 {
+	left := value >> rpsg.halfBits & rpsg.halfMask
+	right := value & rpsg.halfMask
+
+	for round := byte(0); round < randPointsFeistelRounds; round++ {
+		left, right = right, left^rpsg.round(round, right)
+	}
+
+	return left<<rpsg.halfBits | right
+}
+
+func (rpsg *RandPointsSequenceGenerator) round(round byte, value uint64) uint64 {
+	var src, dst [aes.BlockSize]byte
+	src[0] = round
+	binary.BigEndian.PutUint64(src[8:], value)
+	rpsg.block.Encrypt(dst[:], src[:])
+	return binary.BigEndian.Uint64(dst[:8]) & rpsg.halfMask
 }
@@ -96,3 +96,88 @@ func Test_SimplePointsSequenceGenerator_Seek(t *testing.T) {
 		require.Equal(t, offset, g.cursor)
 	}
 }
+
+func Test_SimplePointsSequenceGenerator_Point_DoesNotMoveCursor(t *testing.T) {
+	g := &SimplePointsSequenceGenerator{
+		rect:   image.Rect(-5, -5, 1, 1),
+		cursor: 3,
+	}
+
+	tests := map[uint64]image.Point{
+		0: {-5, -5},
+		4: {-1, -5},
+		6: {-5, -4},
+		9: {-2, -4},
+	}
+
+	for index, point := range tests {
+		require.Equal(t, point, g.Point(index))
+		require.Equal(t, uint64(3), g.cursor)
+	}
+}
+
+func Test_SimplePointsSequenceGenerator_Len(t *testing.T) {
+	require.Equal(t, uint64(0), NewSimplePointsSequenceGenerator(image.Rectangle{}).Len())
+	require.Equal(t, uint64(36), NewSimplePointsSequenceGenerator(image.Rect(-5, -5, 1, 1)).Len())
+	require.Equal(t, uint64(3000), NewSimplePointsSequenceGenerator(image.Rect(0, 0, 30, 100)).Len())
+}
+
+func Test_RandPointsSequenceGenerator_VisitsEveryPointOnce(t *testing.T) {
+	rects := []image.Rectangle{
+		image.Rect(0, 0, 1, 1),
+		image.Rect(0, 0, 2, 1),
+		image.Rect(-5, -5, 1, 1),
+		image.Rect(0, 0, 30, 100),
+		image.Rect(10, 20, 113, 57),
+	}
+
+	for _, rect := range rects {
+		g := NewRandPointsSequenceGenerator(rect, []byte("secret"))
+		require.EqualValues(t, rect.Dx()*rect.Dy(), g.Len(), "Rect %s", rect)
+
+		visited := make(map[image.Point]bool)
+		for ; g.Valid(); g.Next() {
+			point := g.Current()
+			require.True(t, point.In(rect), "Rect %s point %s", rect, point)
+			require.False(t, visited[point], "Rect %s point %s", rect, point)
+			visited[point] = true
+		}
+		require.Len(t, visited, rect.Dx()*rect.Dy(), "Rect %s", rect)
+	}
+}
+
+func Test_RandPointsSequenceGenerator_IsDeterministic(t *testing.T) {
+	rect := image.Rect(0, 0, 40, 25)
+	g1 := NewRandPointsSequenceGenerator(rect, []byte("secret"))
+	g2 := NewRandPointsSequenceGenerator(rect, []byte("secret"))
+	g3 := NewRandPointsSequenceGenerator(rect, []byte("another secret"))
+
+	differs := false
+	for index := uint64(0); index < g1.Len(); index++ {
+		require.Equal(t, g1.Point(index), g2.Point(index))
+		if g1.Point(index) != g3.Point(index) {
+			differs = true
+		}
+	}
+	require.True(t, differs)
+}
+
+func Test_RandPointsSequenceGenerator_Seek(t *testing.T) {
+	g := NewRandPointsSequenceGenerator(image.Rect(0, 0, 30, 100), []byte("secret"))
+
+	tests := []uint64{2, 3, 4, 2, 3, 4, 7, 2, 2999}
+
+	for _, offset := range tests {
+		g.Seek(offset)
+		require.Equal(t, offset, g.cursor)
+		require.True(t, g.Valid())
+		require.Equal(t, g.Point(offset), g.Current())
+	}
+
+	g.Seek(3000)
+	require.False(t, g.Valid())
+
+	g.Rewind()
+	require.Equal(t, uint64(0), g.cursor)
+	require.Equal(t, g.Point(0), g.Current())
+}
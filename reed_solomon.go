@@ -0,0 +1,280 @@
+package imgio
+
+import "errors"
+
+// Reed-Solomon code over GF(2^8) with primitive polynomial x^8+x^4+x^3+x^2+1 and generator 2.
+// Polynomials are stored from the highest degree coefficient to the lowest one
+
+const (
+	gfPrimitive = 0x11d
+	gfOrder     = 255
+
+	// rsCodewordSize is maximal length of Reed-Solomon codeword in bytes
+	rsCodewordSize = gfOrder
+)
+
+var ErrReedSolomon = errors.New("Too many corrupted bytes")
+
+var (
+	gfExp [gfOrder * 2]byte
+	gfLog [gfOrder + 1]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < gfOrder; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x > gfOrder {
+			x ^= gfPrimitive
+		}
+	}
+	for i := gfOrder; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-gfOrder]
+	}
+}
+
+func gfMul(x, y byte) byte {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return gfExp[gfLog[x]+gfLog[y]]
+}
+
+func gfDiv(x, y byte) byte {
+	if x == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[x]+gfOrder-gfLog[y])%gfOrder]
+}
+
+func gfPow(x byte, power int) byte {
+	return gfExp[((gfLog[x]*power)%gfOrder+gfOrder)%gfOrder]
+}
+
+func gfInverse(x byte) byte {
+	return gfExp[gfOrder-gfLog[x]]
+}
+
+func gfPolyScale(p []byte, x byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[i] = gfMul(p[i], x)
+	}
+	return r
+}
+
+func gfPolyAdd(p, q []byte) []byte {
+	size := len(p)
+	if len(q) > size {
+		size = len(q)
+	}
+	r := make([]byte, size)
+	for i := range p {
+		r[i+size-len(p)] = p[i]
+	}
+	for i := range q {
+		r[i+size-len(q)] ^= q[i]
+	}
+	return r
+}
+
+func gfPolyMul(p, q []byte) []byte {
+	r := make([]byte, len(p)+len(q)-1)
+	for j := range q {
+		for i := range p {
+			r[i+j] ^= gfMul(p[i], q[j])
+		}
+	}
+	return r
+}
+
+func gfPolyEval(p []byte, x byte) byte {
+	y := p[0]
+	for i := 1; i < len(p); i++ {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// gfPolyMod returns remainder of division of dividend by monic polynomial divisor
+func gfPolyMod(dividend, divisor []byte) []byte {
+	r := append([]byte{}, dividend...)
+	for i := 0; i < len(dividend)-len(divisor)+1; i++ {
+		coef := r[i]
+		if coef == 0 {
+			continue
+		}
+		for j := 1; j < len(divisor); j++ {
+			r[i+j] ^= gfMul(divisor[j], coef)
+		}
+	}
+	return r[len(r)-len(divisor)+1:]
+}
+
+func reverseBytes(p []byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[len(p)-1-i] = p[i]
+	}
+	return r
+}
+
+// rsGeneratorPoly returns generator polynomial of code with nsym parity symbols
+func rsGeneratorPoly(nsym int) []byte {
+	g := []byte{1}
+	for i := 0; i < nsym; i++ {
+		g = gfPolyMul(g, []byte{1, gfPow(2, i)})
+	}
+	return g
+}
+
+// rsEncode returns nsym parity bytes of message msg. Length of msg must not exceed
+// rsCodewordSize-nsym
+func rsEncode(msg []byte, gen []byte) []byte {
+	nsym := len(gen) - 1
+	r := make([]byte, len(msg)+nsym)
+	copy(r, msg)
+
+	for i := range msg {
+		coef := r[i]
+		if coef == 0 {
+			continue
+		}
+		for j := 1; j < len(gen); j++ {
+			r[i+j] ^= gfMul(gen[j], coef)
+		}
+	}
+
+	return r[len(msg):]
+}
+
+func rsSyndromes(codeword []byte, nsym int) ([]byte, bool) {
+	synd := make([]byte, nsym+1)
+	clean := true
+	for i := 0; i < nsym; i++ {
+		synd[i+1] = gfPolyEval(codeword, gfPow(2, i))
+		if synd[i+1] != 0 {
+			clean = false
+		}
+	}
+	return synd, clean
+}
+
+// rsErrorLocator finds error locator polynomial with Berlekamp-Massey algorithm
+func rsErrorLocator(synd []byte, nsym int) ([]byte, error) {
+	errLoc := []byte{1}
+	oldLoc := []byte{1}
+
+	for i := 0; i < nsym; i++ {
+		k := i + 1
+		delta := synd[k]
+		for j := 1; j < len(errLoc); j++ {
+			delta ^= gfMul(errLoc[len(errLoc)-1-j], synd[k-j])
+		}
+
+		oldLoc = append(oldLoc, 0)
+
+		if delta != 0 {
+			if len(oldLoc) > len(errLoc) {
+				newLoc := gfPolyScale(oldLoc, delta)
+				oldLoc = gfPolyScale(errLoc, gfInverse(delta))
+				errLoc = newLoc
+			}
+			errLoc = gfPolyAdd(errLoc, gfPolyScale(oldLoc, delta))
+		}
+	}
+
+	for len(errLoc) > 0 && errLoc[0] == 0 {
+		errLoc = errLoc[1:]
+	}
+
+	if (len(errLoc)-1)*2 > nsym {
+		return nil, ErrReedSolomon
+	}
+
+	return errLoc, nil
+}
+
+// rsErrorPositions finds positions of errors in codeword of length n with Chien search
+func rsErrorPositions(errLoc []byte, n int) ([]int, error) {
+	errs := len(errLoc) - 1
+	positions := make([]int, 0, errs)
+
+	reversed := reverseBytes(errLoc)
+	for i := 0; i < n; i++ {
+		if gfPolyEval(reversed, gfPow(2, i)) == 0 {
+			positions = append(positions, n-1-i)
+		}
+	}
+
+	if len(positions) != errs {
+		return nil, ErrReedSolomon
+	}
+
+	return positions, nil
+}
+
+// rsCorrectErrata corrects codeword in place with Forney algorithm
+func rsCorrectErrata(codeword, synd []byte, positions []int) {
+	coefPos := make([]int, len(positions))
+	for i, pos := range positions {
+		coefPos[i] = len(codeword) - 1 - pos
+	}
+
+	errLoc := []byte{1}
+	for _, pos := range coefPos {
+		errLoc = gfPolyMul(errLoc, gfPolyAdd([]byte{1}, []byte{gfPow(2, pos), 0}))
+	}
+
+	divisor := make([]byte, len(errLoc)+1)
+	divisor[0] = 1
+	errEval := reverseBytes(gfPolyMod(gfPolyMul(reverseBytes(synd), errLoc), divisor))
+	errEvalRev := reverseBytes(errEval)
+
+	x := make([]byte, len(coefPos))
+	for i, pos := range coefPos {
+		x[i] = gfPow(2, -(gfOrder - pos))
+	}
+
+	for i, xi := range x {
+		xiInv := gfInverse(xi)
+
+		errLocPrime := byte(1)
+		for j := range x {
+			if j != i {
+				errLocPrime = gfMul(errLocPrime, 1^gfMul(xiInv, x[j]))
+			}
+		}
+
+		y := gfMul(xi, gfPolyEval(errEvalRev, xiInv))
+		codeword[positions[i]] ^= gfDiv(y, errLocPrime)
+	}
+}
+
+// rsDecode corrects up to nsym/2 corrupted bytes of codeword in place
+func rsDecode(codeword []byte, nsym int) error {
+	synd, clean := rsSyndromes(codeword, nsym)
+	if clean {
+		return nil
+	}
+
+	errLoc, err := rsErrorLocator(synd, nsym)
+	if err != nil {
+		return err
+	}
+
+	positions, err := rsErrorPositions(errLoc, len(codeword))
+	if err != nil {
+		return err
+	}
+
+	rsCorrectErrata(codeword, synd, positions)
+
+	if _, clean := rsSyndromes(codeword, nsym); !clean {
+		return ErrReedSolomon
+	}
+
+	return nil
+}
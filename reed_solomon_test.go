@@ -0,0 +1,62 @@
+package imgio
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_gfMulDiv(t *testing.T) {
+	for x := 1; x < 256; x++ {
+		require.Equal(t, byte(1), gfMul(byte(x), gfInverse(byte(x))), "x = %d", x)
+		for y := 1; y < 256; y += 17 {
+			require.Equal(t, byte(x), gfDiv(gfMul(byte(x), byte(y)), byte(y)), "x = %d, y = %d", x, y)
+		}
+	}
+}
+
+func Test_rsEncode(t *testing.T) {
+	// Known vector of the code with 10 parity symbols
+	msg := []byte{0x40, 0xd2, 0x75, 0x47, 0x76, 0x17, 0x32, 0x06, 0x27, 0x26, 0x96, 0xc6, 0xc6, 0x96, 0x70, 0xec}
+	parity := rsEncode(msg, rsGeneratorPoly(10))
+	require.Equal(t, []byte{0xbc, 0x2a, 0x90, 0x13, 0x6b, 0xaf, 0xef, 0xfd, 0x4b, 0xe0}, parity)
+}
+
+func Test_rsDecode(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		length int
+		nsym   int
+		errors int
+
+		expectedErr error
+	}{
+		{20, 10, 0, nil},
+		{20, 10, 1, nil},
+		{20, 10, 5, nil},
+		{255, 32, 16, nil},
+		{100, 64, 32, nil},
+		{255, 2, 1, nil},
+		{20, 10, 6, ErrReedSolomon},
+		{255, 32, 17, ErrReedSolomon},
+	}
+
+	for i, test := range tests {
+		msg := make([]byte, test.length-test.nsym)
+		random.Read(msg)
+		codeword := append(append([]byte{}, msg...), rsEncode(msg, rsGeneratorPoly(test.nsym))...)
+
+		for _, pos := range random.Perm(len(codeword))[:test.errors] {
+			codeword[pos] ^= byte(random.Intn(255) + 1)
+		}
+
+		err := rsDecode(codeword, test.nsym)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		if err == nil {
+			require.True(t, bytes.Equal(msg, codeword[:len(msg)]), "Test index %d", i)
+		}
+	}
+}
@@ -1,9 +1,41 @@
 package imgio
 
 import (
+	"errors"
 	"io"
 )
 
 type Storage interface {
 	io.ReadWriteSeeker
 }
+
+var (
+	ErrSeekWhence     = errors.New("Invalid whence")
+	ErrSeekNegative   = errors.New("Negative position")
+	ErrSeekOutOfRange = errors.New("Position out of range")
+)
+
+// seekPosition returns absolute position for offset relative to whence
+func seekPosition(offset int64, whence int, current, size int64) (int64, error) {
+	var pos int64
+
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = current + offset
+	case io.SeekEnd:
+		pos = size + offset
+	default:
+		return 0, ErrSeekWhence
+	}
+
+	if pos < 0 {
+		return 0, ErrSeekNegative
+	}
+	if pos > size {
+		return 0, ErrSeekOutOfRange
+	}
+
+	return pos, nil
+}
@@ -0,0 +1,308 @@
+package imgio
+
+import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"image"
+	"image/draw"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+	"testing/quick"
+
+	"gopkg.in/stretchr/testify.v1/require"
+)
+
+func Test_seekPosition(t *testing.T) {
+	tests := []struct {
+		offset  int64
+		whence  int
+		current int64
+		size    int64
+
+		expectedPos int64
+		expectedErr error
+	}{
+		{0, io.SeekStart, 5, 10, 0, nil},
+		{7, io.SeekStart, 5, 10, 7, nil},
+		{10, io.SeekStart, 5, 10, 10, nil},
+		{11, io.SeekStart, 5, 10, 0, ErrSeekOutOfRange},
+		{-1, io.SeekStart, 5, 10, 0, ErrSeekNegative},
+		{2, io.SeekCurrent, 5, 10, 7, nil},
+		{-5, io.SeekCurrent, 5, 10, 0, nil},
+		{-6, io.SeekCurrent, 5, 10, 0, ErrSeekNegative},
+		{0, io.SeekEnd, 5, 10, 10, nil},
+		{-10, io.SeekEnd, 5, 10, 0, nil},
+		{1, io.SeekEnd, 5, 10, 0, ErrSeekOutOfRange},
+		{0, 3, 5, 10, 0, ErrSeekWhence},
+	}
+
+	for i, test := range tests {
+		pos, err := seekPosition(test.offset, test.whence, test.current, test.size)
+		require.Equal(t, test.expectedErr, err, "Test index %d", i)
+		require.Equal(t, test.expectedPos, pos, "Test index %d", i)
+	}
+}
+
+func newChannelBitsImage(img draw.Image, bits int, mask ChannelMask) Storage {
+	prw, err := NewChannelBitsReadWriter(bits, mask)
+	if err != nil {
+		panic(err)
+	}
+	return NewImage(img, NewSimplePointsSequenceGenerator(img.Bounds()), prw)
+}
+
+var jpegCover = newNoiseJPEG(image.NewRGBA(image.Rect(0, 0, 24, 16)), 90)
+
+// testStorages returns constructors of storages of every codec and engine
+func testStorages() map[string]func() Storage {
+	rect := image.Rect(0, 0, 17, 13)
+
+	return map[string]func() Storage{
+		"SimplePoint32": func() Storage {
+			return NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{})
+		},
+		"SimplePoint64": func() Storage {
+			return NewImage(image.NewRGBA64(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint64ReadWriter{})
+		},
+		"GentlePoint16": func() Storage {
+			return NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), GentlePoint16ReadWriter{})
+		},
+		"SmartPoint8": func() Storage {
+			return NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SmartPoint8ReadWriter{})
+		},
+		"ChannelBits1RGB": func() Storage {
+			return newChannelBitsImage(image.NewRGBA(rect), 1, ChannelRGB)
+		},
+		"ChannelBits3RGBA": func() Storage {
+			return newChannelBitsImage(image.NewNRGBA(rect), 3, ChannelRGBA)
+		},
+		"ChannelBits5RB": func() Storage {
+			return newChannelBitsImage(image.NewRGBA(rect), 5, ChannelR|ChannelB)
+		},
+		"ChannelBits13RGBA": func() Storage {
+			return newChannelBitsImage(image.NewNRGBA64(rect), 13, ChannelRGBA)
+		},
+		"RandPoints": func() Storage {
+			return NewImage(image.NewRGBA(rect), NewRandPointsSequenceGenerator(rect, []byte("secret")), GentlePoint16ReadWriter{})
+		},
+		"RGBASimple": func() Storage {
+			return NewImageReadWriterRGBA(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), PointReadWriterRGBASimple{})
+		},
+		"YCbCrSimple": func() Storage {
+			return NewImageReadWriterYCbCr(
+				image.NewYCbCr(rect, image.YCbCrSubsampleRatio444),
+				NewSimplePointsSequenceGenerator(rect),
+				PointReadWriterYCbCrSimple{},
+			)
+		},
+		"PointStorageNRGBA": func() Storage {
+			img := image.NewNRGBA(rect)
+			draw.Draw(img, rect, image.Opaque, image.ZP, draw.Src)
+			return NewPointStorage(NewImageAccessor(img, SmartPoint8ReadWriter{}), NewSimplePointsSequenceGenerator(rect))
+		},
+		"NRGBAPoint32": func() Storage {
+			return NewImage(image.NewNRGBA(rect), NewSimplePointsSequenceGenerator(rect), NRGBAPoint32ReadWriter{})
+		},
+		"NRGBAPoint64": func() Storage {
+			return NewPointStorage(NewImageAccessor(image.NewNRGBA64(rect), NRGBAPoint64ReadWriter{}), NewSimplePointsSequenceGenerator(rect))
+		},
+		"PointStorageYCbCr": func() Storage {
+			return NewPointStorage(
+				NewYCbCrAccessor(image.NewYCbCr(rect, image.YCbCrSubsampleRatio444), PointReadWriterYCbCrLuma{}),
+				NewRandPointsSequenceGenerator(rect, []byte("secret")),
+			)
+		},
+		"FEC": func() Storage {
+			storage, err := NewFECStorage(
+				NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint32ReadWriter{}),
+				16,
+			)
+			if err != nil {
+				panic(err)
+			}
+			return storage
+		},
+		"JPEG": func() Storage {
+			coeffs, err := DecodeJPEGCoefficients(bytes.NewReader(jpegCover))
+			if err != nil {
+				panic(err)
+			}
+			return NewJPEGReadWriter(coeffs, NewSimplePointsSequenceGenerator(coeffs.Bounds()))
+		},
+		"ImageGroup": func() Storage {
+			return NewImageGroup(
+				NewImage(image.NewRGBA(rect), NewSimplePointsSequenceGenerator(rect), SmartPoint8ReadWriter{}),
+				newChannelBitsImage(image.NewRGBA(image.Rect(0, 0, 3, 7)), 1, ChannelRGB).(*rwImage),
+				NewImage(image.NewRGBA64(rect), NewSimplePointsSequenceGenerator(rect), SimplePoint64ReadWriter{}),
+			)
+		},
+	}
+}
+
+type sizer interface {
+	Size() int64
+}
+
+// writeChunks writes data to w by chunks of size bytes
+func writeChunks(w io.Writer, data []byte, size int) (n int, err error) {
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		var written int
+		written, err = w.Write(chunk)
+		n += written
+		data = data[written:]
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// readChunks reads total bytes from r by chunks of size bytes
+func readChunks(r io.Reader, total, size int) ([]byte, error) {
+	data := make([]byte, 0, total)
+	buff := make([]byte, size)
+
+	for len(data) < total {
+		if total-len(data) < size {
+			buff = buff[:total-len(data)]
+		}
+
+		n, err := r.Read(buff)
+		data = append(data, buff[:n]...)
+		if err != nil {
+			return data, err
+		}
+	}
+
+	return data, nil
+}
+
+func randomBytes(t *testing.T, size int64) []byte {
+	data := make([]byte, size)
+	_, err := cryptorand.Read(data)
+	require.Nil(t, err)
+	return data
+}
+
+func Test_Storage_ReadWrite_Chunks(t *testing.T) {
+	chunkSizes := []int{1, 2, 3, 5, 7, 11, 13, 31, 127, 4096}
+
+	for name, newStorage := range testStorages() {
+		for _, writeSize := range chunkSizes {
+			for _, readSize := range chunkSizes {
+				storage := newStorage()
+				data := randomBytes(t, storage.(sizer).Size())
+
+				n, err := writeChunks(storage, data, writeSize)
+				require.Nil(t, err, "%s: write by %d", name, writeSize)
+				require.Equal(t, len(data), n, "%s: write by %d", name, writeSize)
+
+				_, err = storage.Seek(0, io.SeekStart)
+				require.Nil(t, err, "%s", name)
+
+				buff, err := readChunks(storage, len(data), readSize)
+				if err != nil {
+					require.Equal(t, io.EOF, err, "%s: read by %d", name, readSize)
+				}
+				require.Equal(t, data, buff, "%s: write by %d, read by %d", name, writeSize, readSize)
+			}
+		}
+	}
+}
+
+func Test_Storage_ReadWrite_IOTestReaders(t *testing.T) {
+	wrappers := map[string]func(io.Reader) io.Reader{
+		"OneByteReader": iotest.OneByteReader,
+		"HalfReader":    iotest.HalfReader,
+	}
+
+	for name, newStorage := range testStorages() {
+		for writeWrapper, wrapWrite := range wrappers {
+			for readWrapper, wrapRead := range wrappers {
+				storage := newStorage()
+				data := randomBytes(t, storage.(sizer).Size())
+
+				n, err := io.Copy(storage, wrapWrite(bytes.NewReader(data)))
+				require.Nil(t, err, "%s: %s", name, writeWrapper)
+				require.EqualValues(t, len(data), n, "%s: %s", name, writeWrapper)
+
+				_, err = storage.Seek(0, io.SeekStart)
+				require.Nil(t, err, "%s", name)
+
+				buff, err := ioutil.ReadAll(wrapRead(storage))
+				require.Nil(t, err, "%s: %s", name, readWrapper)
+				require.Equal(t, data, buff, "%s: write with %s, read with %s", name, writeWrapper, readWrapper)
+			}
+		}
+	}
+}
+
+func Test_Storage_ReadWrite_Property(t *testing.T) {
+	for name, newStorage := range testStorages() {
+		property := func(seed int64, length uint16, writeSize, readSize uint8) bool {
+			storage := newStorage()
+			random := rand.New(rand.NewSource(seed))
+			data := make([]byte, int64(length)%(storage.(sizer).Size()+1))
+			random.Read(data)
+
+			n, err := writeChunks(storage, data, int(writeSize)+1)
+			if err != nil || n != len(data) {
+				return false
+			}
+
+			if _, err := storage.Seek(0, io.SeekStart); err != nil {
+				return false
+			}
+
+			buff, err := readChunks(storage, len(data), int(readSize)+1)
+			if err != nil && err != io.EOF {
+				return false
+			}
+
+			return bytes.Equal(data, buff)
+		}
+
+		require.Nil(t, quick.Check(property, nil), "%s", name)
+	}
+}
+
+func Test_Storage_ReadAtWriteAt_Property(t *testing.T) {
+	for name, newStorage := range testStorages() {
+		storage := newStorage()
+		size := storage.(sizer).Size()
+		shadow := make([]byte, size)
+		_, err := storage.Write(shadow)
+		require.Nil(t, err, "%s", name)
+
+		property := func(seed int64, offset, length uint16) bool {
+			random := rand.New(rand.NewSource(seed))
+			off := int64(offset) % size
+			data := make([]byte, int64(length)%(size-off+1))
+			random.Read(data)
+
+			n, err := storage.(io.WriterAt).WriteAt(data, off)
+			if err != nil || n != len(data) {
+				return false
+			}
+			copy(shadow[off:], data)
+
+			buff := make([]byte, size)
+			n, err = storage.(io.ReaderAt).ReadAt(buff, 0)
+			if err != nil || n != len(buff) {
+				return false
+			}
+
+			return bytes.Equal(shadow, buff)
+		}
+
+		require.Nil(t, quick.Check(property, nil), "%s", name)
+	}
+}